@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	_ "time/tzdata" // в alpine образе нет базы часовых поясов
 
 	"github.com/Silverman143/character-service/internal/app"
 	referralgrpc "github.com/Silverman143/character-service/internal/clients/referral/grpc"
@@ -16,13 +17,18 @@ import (
 	kafkaproducer "github.com/Silverman143/character-service/internal/kafka/producer"
 	slogpretty "github.com/Silverman143/character-service/internal/lib/cachekeys/logger/pretter"
 	cache "github.com/Silverman143/character-service/internal/redis"
+	characterservice "github.com/Silverman143/character-service/internal/services/character"
 	"github.com/Silverman143/character-service/internal/storage/postgres"
+	seasonworker "github.com/Silverman143/character-service/internal/workers/season"
 )
 
 const(
 	envLocal = "local"
 	envDev = "dev"
 	envProd = "prod"
+
+	cacheBackendRedis = "redis"
+	cacheBackendMemory = "memory"
 )
 
 func main(){
@@ -44,12 +50,28 @@ func main(){
     defer storage.Stop()
 
 
-    cache, err := cache.NewRedisCache(cfg.Redis, log)
-    if err != nil {
-        log.Error("Failed to connect to Redis", slog.String("error", err.Error()))
+    var (
+        characterCache characterservice.Cache
+        layeredCache *cache.LayeredCache
+    )
+    switch cfg.Redis.Backend {
+    case cacheBackendMemory:
+        log.Warn("Using in-memory cache, it is not shared between instances")
+        characterCache = cache.NewMemoryCache(cfg.Redis.Memory)
+    case cacheBackendRedis:
+        redisCache, err := cache.NewRedisCache(cfg.Redis, log)
+        if err != nil {
+            log.Error("Failed to connect to Redis", slog.String("error", err.Error()))
+            os.Exit(1)
+        }
+        defer redisCache.Close()
+
+        layeredCache = cache.NewLayeredCache(redisCache, cfg.Redis.Local)
+        characterCache = layeredCache
+    default:
+        log.Error("Unknown cache backend", slog.String("backend", cfg.Redis.Backend))
         os.Exit(1)
     }
-    defer cache.Close()
 
     kafkaProducer, err := kafkaproducer.NewKafkaProducer(cfg.Kafka, log)
     if err != nil {
@@ -58,12 +80,6 @@ func main(){
     }
     defer kafkaProducer.Close()
 
-    kafkaConsumer, err := kafkaconsumer.NewKafkaConsumer(cfg.Kafka, log)
-    if err != nil{
-        log.Error("Failed to create kafka consumer", slog.String("error", err.Error()))
-        os.Exit(1)
-    }
-
 	userClient, err := usergrpc.New(ctx, log, &cfg.Clients.User)
 	if err != nil{
 		log.Error("Failed to connect to UserClient", slog.String("error", err.Error()))
@@ -76,11 +92,40 @@ func main(){
         os.Exit(1)
 	}
 
-    application := app.New(log, cfg, storage, cache, kafkaProducer, userClient, referralClient)
+    application := app.New(log, cfg, storage, characterCache, kafkaProducer, userClient, referralClient)
+
+    kafkaConsumer, err := kafkaconsumer.NewKafkaConsumer(cfg.Kafka, log, application.CharacterService)
+    if err != nil{
+        log.Error("Failed to create kafka consumer", slog.String("error", err.Error()))
+        os.Exit(1)
+    }
+
+    // Прогрев каталога до запуска gRPC сервера, ошибка прогрева не мешает работе
+    warmupCtx, warmupCancel := context.WithTimeout(ctx, cfg.Warmup.Timeout)
+    if err := application.CharacterService.WarmupCatalog(warmupCtx); err != nil {
+        log.Warn("Failed to warm up cache", slog.String("error", err.Error()))
+    }
+    warmupCancel()
 
     // Используем WaitGroup для ожидания завершения всех горутин
     var wg sync.WaitGroup
 
+    // Предзагрузка недавно активных персонажей, до ее окончания сервис не готов
+    if cfg.Warmup.RecentCharacters > 0 {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            preloadCtx, preloadCancel := context.WithTimeout(ctx, cfg.Warmup.Timeout)
+            defer preloadCancel()
+            if _, err := application.CharacterService.PreloadRecentCharacters(preloadCtx); err != nil {
+                log.Warn("Failed to preload recent characters", slog.String("error", err.Error()))
+            }
+            application.GRPCServer.SetServing(true)
+        }()
+    } else {
+        application.GRPCServer.SetServing(true)
+    }
+
     // Запуск gRPC сервера
     wg.Add(1)
     go func() {
@@ -97,6 +142,31 @@ func main(){
         kafkaConsumer.RunConsumer(ctx)
     }()
 
+    // Запуск подписки на инвалидации локального кэша
+    if layeredCache != nil {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if err := layeredCache.Run(ctx); err != nil {
+                log.Error("cache invalidations subscription error", slog.String("error", err.Error()))
+            }
+        }()
+    }
+
+    // Проверка реплик Postgres, отстающие и недоступные исключаются из чтений
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        storage.RunHealthChecks(ctx, log)
+    }()
+
+    // Запуск воркера смены сезонов
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        seasonworker.New(log, application.CharacterService, cfg.Seasons.RolloverInterval).Run(ctx)
+    }()
+
     // Ожидание сигнала для завершения
     stop := make(chan os.Signal, 1)
     signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
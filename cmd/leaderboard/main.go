@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/Silverman143/character-service/internal/config"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	characterservice "github.com/Silverman143/character-service/internal/services/character"
+	"github.com/Silverman143/character-service/internal/storage/postgres"
+)
+
+// Пересобирает таблицы лидеров в Redis из таблицы characters
+func main() {
+	cfg := config.MustLoad()
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	storage, err := postgres.New(&cfg.PgSql)
+	if err != nil {
+		log.Error("Failed to connect to postgres", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer storage.Stop()
+
+	redisCache, err := cache.NewRedisCache(cfg.Redis, log)
+	if err != nil {
+		log.Error("Failed to connect to Redis", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer redisCache.Close()
+
+	repo := postgres.NewRepository(storage)
+	// Локальный уровень кэша одноразовому процессу не нужен
+	layeredCache := cache.NewLayeredCache(redisCache, config.LocalCacheConfig{})
+	service := characterservice.New(log, cfg, repo, characterservice.Deps{Cache: layeredCache})
+
+	total, err := service.RebuildLeaderboards(context.Background())
+	if err != nil {
+		log.Error("Failed to rebuild leaderboards", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	log.Info("Leaderboards rebuilt successfully", slog.Int("characters", total))
+}
@@ -8,7 +8,6 @@ import (
 	usergrpc "github.com/Silverman143/character-service/internal/clients/user/grpc"
 	"github.com/Silverman143/character-service/internal/config"
 	kafkaproducer "github.com/Silverman143/character-service/internal/kafka/producer"
-	cache "github.com/Silverman143/character-service/internal/redis"
 	characterService "github.com/Silverman143/character-service/internal/services/character"
 	"github.com/Silverman143/character-service/internal/storage/postgres"
 )
@@ -16,25 +15,32 @@ import (
 type App struct {
 	GRPCServer *grpcapp.App
 	KafkaProducer *kafkaproducer.KafkaProducer
+	CharacterService *characterService.Character
 
 }
 
 func New (	log *slog.Logger, 
 			config *config.Config, 
 			storage *postgres.Storage, 
-			cache *cache.RedisCache, 
+			cache characterService.Cache, 
 			kafkaProducer *kafkaproducer.KafkaProducer,
 			userClient *usergrpc.Client, 
 			referralClient *referralgrpc.Client  ) *App{
 
 	repo := postgres.NewRepository(storage)
 
-	characterService := characterService.New(log, repo, repo, cache, kafkaProducer, userClient, referralClient)
+	characterService := characterService.New(log, config, repo, characterService.Deps{
+		Cache:          cache,
+		KafkaProducer:  kafkaProducer,
+		UserClient:     userClient,
+		ReferralClient: referralClient,
+	})
 
 	gRPCApp := grpcapp.New(log, characterService, config.GRPC.Port)
 
 	return &App{
 		GRPCServer: gRPCApp,
 		KafkaProducer: kafkaProducer,
+		CharacterService: characterService,
 	}
 }
@@ -13,12 +13,15 @@ import (
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
 type App struct {
 	log 	*slog.Logger
 	gRPCServer 	*grpc.Server
+	health	*health.Server
 	port	 int
 }
 
@@ -50,13 +53,28 @@ func New(
 
 	charactergrpc.Register(gRPCServer, characterService)
 
+	// Сервис не готов, пока не прогрет кэш, см. SetServing
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(gRPCServer, healthServer)
+
 	return &App{
 		log:        log,
 		gRPCServer: gRPCServer,
+		health:     healthServer,
 		port:       port,
 	}
 }
 
+// SetServing - switches health status reported to probes
+func (a *App) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	a.health.SetServingStatus("", status)
+}
+
 // InterceptorLogger adapts slog logger to interceptor logger.
 // This code is simple enough to be copied and not imported.
 func InterceptorLogger(l *slog.Logger) logging.Logger {
@@ -99,5 +117,6 @@ func (a *App) Stop() {
 
 	a.log.With(slog.String("op", op)).Info("stopping gRPC server")
 
+	a.health.Shutdown()
 	a.gRPCServer.GracefulStop()
 }
\ No newline at end of file
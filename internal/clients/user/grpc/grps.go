@@ -86,4 +86,14 @@ func (c *Client) FinalizePayment(ctx context.Context, paymentID string, success
     }
     
     return nil
-}
\ No newline at end of file
+}
+func (c *Client) AddCoins(ctx context.Context, userID int64, amount int64) (int64, error) {
+    const op = "clients.user.grpc.AddCoins"
+
+    resp, err := c.api.AddCoinsToUser(ctx, &userv1.AddCoinsToUserRequest{UserId: userID, CoinsAmount: amount})
+    if err != nil {
+        return 0, fmt.Errorf("%s: failed to add coins: %w", op, err)
+    }
+
+    return resp.CoinsTotal, nil
+}
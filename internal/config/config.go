@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -10,6 +11,8 @@ import (
 	"github.com/joho/godotenv"
 )
 
+const envProd = "prod"
+
 type Config struct{
 	Env 				string 			`yaml:"env" env-default:"local"`
 	PgSql 				PgSql 			`env-required:"true"`
@@ -17,6 +20,16 @@ type Config struct{
 	GRPC 				GRPCConfig 		`yaml:"grpc" env-required:"true"`
 	Kafka				KafkaConfig		`yaml:"kafka" env-required:"true"`
 	Clients				ClientsConfig	`yaml:"clients" `
+	Seasons				SeasonsConfig	`yaml:"seasons"`
+	Gifts				GiftsConfig		`yaml:"gifts"`
+	ReferralMilestones	[]ReferralMilestone	`yaml:"referral_milestones"`
+	DailyRewards		DailyRewardsConfig	`yaml:"daily_rewards"`
+	Nicknames			NicknamesConfig		`yaml:"nicknames"`
+	Attributes			AttributesConfig	`yaml:"attributes"`
+	PromoCodes			PromoCodesConfig	`yaml:"promo_codes"`
+	Fraud				FraudConfig			`yaml:"fraud"`
+	Warmup				WarmupConfig		`yaml:"warmup"`
+	Characters			CharactersConfig	`yaml:"characters"`
 }
 
 type PgSql struct {
@@ -26,6 +39,16 @@ type PgSql struct {
 	DbName   string `env:"POSTGRES_DB,required"`
 	Port     int    `env:"POSTGRES_PORT" env-default:"5432"`
 	SSLMode  string `env:"POSTGRES_SSLMODE" env-default:"disable"`
+	// ReplicaDSNs - optional read replicas, read-only queries are routed to healthy ones
+	ReplicaDSNs          []string      `env:"POSTGRES_REPLICA_DSNS" env-separator:","`
+	ReplicaCheckInterval time.Duration `env:"POSTGRES_REPLICA_CHECK_INTERVAL" env-default:"5s"`
+	// ReplicaMaxLag - replica lagging behind the primary more than this is not used for reads
+	ReplicaMaxLag        time.Duration `env:"POSTGRES_REPLICA_MAX_LAG" env-default:"10s"`
+	// TxIsolation - default isolation of transactions: read committed, repeatable read or serializable
+	TxIsolation          string        `env:"POSTGRES_TX_ISOLATION" env-default:"read committed"`
+	// TxRetries - how many times transaction failed to serialize or deadlocked is run again
+	TxRetries            int           `env:"POSTGRES_TX_RETRIES" env-default:"3"`
+	TxRetryDelay         time.Duration `env:"POSTGRES_TX_RETRY_DELAY" env-default:"10ms"`
 }
 
 type GRPCConfig struct {
@@ -42,11 +65,39 @@ type KafkaConfig struct{
 	Pass 			string 		`env:"KAFKA_PASS,required"`
 }
 
+// RedisConfig - cache settings, connection is required only for redis backend
 type RedisConfig struct{
-	Addr     string `env:"REDIS_ADDR,required"`
-	Password string `env:"REDIS_PASSWORD,required"`
-	DB       int    `env:"REDIS_DB,required"`
+	Backend  string `yaml:"backend" env:"CACHE_BACKEND" env-default:"redis"`
+	Addr     string `env:"REDIS_ADDR"`
+	Password string `env:"REDIS_PASSWORD"`
+	DB       int    `env:"REDIS_DB"`
 	Lifetime time.Duration `yaml:"lifetime" env-required:"true"`
+	Memory   MemoryCacheConfig `yaml:"memory"`
+	// BinaryKeyClasses - key prefixes encoded with compact binary codec instead of JSON
+	BinaryKeyClasses []string `yaml:"binary_key_classes"`
+	Local    LocalCacheConfig `yaml:"local"`
+	// TTLJitter - fraction of the TTL randomly added to loaded entries, so they do not expire at once
+	TTLJitter float64 `yaml:"ttl_jitter" env-default:"0.1"`
+	// LoadLock - how long one instance may load a missed key while others wait for it, zero disables the lock
+	LoadLock time.Duration `yaml:"load_lock" env-default:"0s"`
+}
+
+// MemoryCacheConfig - in-process backend replacing Redis, Size is max entries count
+type MemoryCacheConfig struct {
+	Size				int					`yaml:"size" env-default:"100000"`
+}
+
+// LocalCacheConfig - in-process tier in front of Redis, only keys of the listed classes are kept in memory.
+// Size is max entries count, zero size disables the tier.
+type LocalCacheConfig struct {
+	Size				int					`yaml:"size" env-default:"0"`
+	KeyClasses			[]LocalKeyClass		`yaml:"key_classes"`
+}
+
+// LocalKeyClass - keys with the prefix are cached locally for TTL, but not longer than in Redis
+type LocalKeyClass struct {
+	Prefix				string				`yaml:"prefix"`
+	TTL					time.Duration		`yaml:"ttl"`
 }
 
 type Client struct {
@@ -56,6 +107,95 @@ type Client struct {
 	Insecure 		bool			`yaml:"insecure" env-required:"true"`
 }
 
+type SeasonsConfig struct {
+	MiningCoinsPerXP	int64			`yaml:"mining_coins_per_xp" env-default:"10"`
+	XPPerGame			int64			`yaml:"xp_per_game" env-default:"5"`
+	RolloverInterval	time.Duration	`yaml:"rollover_interval" env-default:"1m"`
+}
+
+type GiftsConfig struct {
+	DailyLimit			int64			`yaml:"daily_limit" env-default:"3"`
+}
+
+// RewardConfig - reward description, amount is coins amount, boost percent or levels count
+type RewardConfig struct {
+	Type				string			`yaml:"reward_type"`
+	Amount				int64			`yaml:"amount"`
+	SkinID				*int			`yaml:"skin_id"`
+	BoostType			string			`yaml:"boost_type"`
+	DurationMinutes		int				`yaml:"duration_minutes"`
+}
+
+type ReferralMilestone struct {
+	Referrals			int				`yaml:"referrals"`
+	Reward				RewardConfig	`yaml:"reward"`
+}
+
+// DailyRewardsConfig - reward of every streak day, calendar starts over after the last day
+type DailyRewardsConfig struct {
+	Calendar			[]RewardConfig	`yaml:"calendar"`
+}
+
+// NicknamesConfig - nickname rules, rename during cooldown is allowed only for RenamePrice coins when it is set
+type NicknamesConfig struct {
+	MinLength			int				`yaml:"min_length" env-default:"3"`
+	MaxLength			int				`yaml:"max_length" env-default:"20"`
+	Blocklist			[]string		`yaml:"blocklist"`
+	RenameCooldown		time.Duration	`yaml:"rename_cooldown" env-default:"720h"`
+	RenamePrice			int64			`yaml:"rename_price" env-default:"0"`
+}
+
+// AttributesConfig - stat points granted for every level above the first and modifiers of every attribute
+type AttributesConfig struct {
+	PointsPerLevel		int					`yaml:"points_per_level" env-default:"1"`
+	RespecPrice			int64				`yaml:"respec_price" env-default:"0"`
+	Strength			AttributeFormula	`yaml:"strength"`
+	Luck				AttributeFormula	`yaml:"luck"`
+	Stamina				AttributeFormula	`yaml:"stamina"`
+}
+
+// AttributeFormula - percents added to derived stats by one allocated point
+type AttributeFormula struct {
+	MiningForcePercent		int		`yaml:"mining_force_percent"`
+	MiningDurationPercent	int		`yaml:"mining_duration_percent"`
+	GameMultiplierPercent	int		`yaml:"game_multiplier_percent"`
+}
+
+// PromoCodesConfig - length of the random part of generated codes and max codes in one batch
+type PromoCodesConfig struct {
+	CodeLength			int				`yaml:"code_length" env-default:"10"`
+	MaxBatchSize		int				`yaml:"max_batch_size" env-default:"10000"`
+}
+
+// FraudConfig - sliding window signals and score thresholds of the fraud check.
+// Every triggered signal adds its score, zero threshold disables the signal.
+type FraudConfig struct {
+	Window				time.Duration	`yaml:"window" env-default:"1h"`
+	MaxOperations		int64			`yaml:"max_operations" env-default:"10"`
+	VelocityScore		int				`yaml:"velocity_score" env-default:"40"`
+	MaxReferralJump		int				`yaml:"max_referral_jump" env-default:"20"`
+	ReferralJumpScore	int				`yaml:"referral_jump_score" env-default:"50"`
+	MaxRollbacks		int64			`yaml:"max_rollbacks" env-default:"3"`
+	RollbacksScore		int				`yaml:"rollbacks_score" env-default:"30"`
+	ReviewScore			int				`yaml:"review_score" env-default:"50"`
+	BlockScore			int				`yaml:"block_score" env-default:"80"`
+}
+
+// WarmupConfig - cache warmup at startup, RecentCharacters most recently active characters are preloaded
+// in background, service reports not ready until they are loaded. Zero RecentCharacters disables preloading.
+type WarmupConfig struct {
+	Timeout				time.Duration	`yaml:"timeout" env-default:"30s"`
+	RecentCharacters	int64			`yaml:"recent_characters" env-default:"0"`
+	Concurrency			int				`yaml:"concurrency" env-default:"10"`
+}
+
+// CharactersConfig - unknown users are remembered for MissingTTL, so repeated reads do not reach Postgres.
+// With AutoCreate character is created on the first read instead.
+type CharactersConfig struct {
+	MissingTTL			time.Duration	`yaml:"missing_ttl" env-default:"1m"`
+	AutoCreate			bool			`yaml:"auto_create" env-default:"false"`
+}
+
 type ClientsConfig struct {
 	User 		Client	`yaml:"user" env-required:"true"`
 	Referral 	Client	`yaml:"referral" env-required:"true"`
@@ -88,9 +228,51 @@ func MustLoad()	*Config {
 		panic("failed to read env: " + err.Error())
 	}
 
+	if err := config.validate(); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+
 	return &config
 }
 
+// validate - in production game, attributes and fraud sections must be set, their defaults are empty
+// and features silently stop working
+func (c *Config) validate() error {
+	if c.Env != envProd {
+		return nil
+	}
+
+	switch {
+	case len(c.DailyRewards.Calendar) == 0:
+		return errors.New("daily_rewards calendar is empty")
+	case len(c.ReferralMilestones) == 0:
+		return errors.New("referral_milestones are empty")
+	case len(c.Nicknames.Blocklist) == 0:
+		return errors.New("nicknames blocklist is empty")
+	case c.Gifts.DailyLimit <= 0:
+		return errors.New("gifts daily_limit must be positive")
+	case c.Seasons.RolloverInterval <= 0:
+		return errors.New("seasons rollover_interval must be positive")
+	case c.Redis.Backend != "redis":
+		// Кэш в памяти процесса не делится между инстансами
+		return errors.New("cache backend must be redis")
+	case c.Attributes.PointsPerLevel <= 0:
+		return errors.New("attributes points_per_level must be positive")
+	case c.Attributes.Strength.isZero(), c.Attributes.Luck.isZero(), c.Attributes.Stamina.isZero():
+		return errors.New("attributes formulas must be set for every attribute")
+	case c.Fraud.Window <= 0:
+		return errors.New("fraud window must be positive")
+	case c.Fraud.ReviewScore <= 0 || c.Fraud.BlockScore < c.Fraud.ReviewScore:
+		return errors.New("fraud review_score must be positive and not above block_score")
+	}
+
+	return nil
+}
+
+func (f AttributeFormula) isZero() bool {
+	return f == AttributeFormula{}
+}
+
 func fetchConfigFlag() string {
 	var res string
 
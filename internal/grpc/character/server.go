@@ -1,12 +1,15 @@
 package character
 
 import (
+	"errors"
+
 	characterservice "github.com/Silverman143/character-service/internal/services/character"
 	"github.com/Silverman143/character-service/internal/services/character/dto"
 	characterv1 "github.com/Silverman143/protos_chadnaldo/gen/go/character"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -15,7 +18,7 @@ type Character interface {
 	CreateCharacter(ctx context.Context, user_id int64) error
 	GetCharacter(ctx context.Context, user_id int64)(*dto.GetCharacterDTO, error)
 	GetSkins(ctx context.Context, user_id int64)(*dto.GetSkinsDTO, error)
-	LevelUpCharacter(ctx context.Context, userID int64) (newLevel *int, coinsBalance *int64, err error)
+	LevelUpCharacter(ctx context.Context, userID int64, method string) (*dto.LevelUpResultDTO, error)
 	ChangeActiveSkin(ctx context.Context, userID int64, skinID int32 ) error
 }
 
@@ -31,6 +34,10 @@ func Register(gRPCServer *grpc.Server, 	character *characterservice.Character) {
 const (
 	emptyValue = ""
 	emptyInt = 0
+	// paymentMethodHeader - metadata key with requested and applied level up payment method
+	paymentMethodHeader = "x-payment-method"
+	// nicknameHeader - response metadata key with the character nickname, empty before the first rename
+	nicknameHeader = "x-nickname"
 )
 
 func (s *serverAPI) GetCharacterLevel (ctx context.Context, req *characterv1.GetCharacterLevelRequest) (*characterv1.GetCharacterLevelResponse, error ){
@@ -76,6 +83,12 @@ func (s *serverAPI) GetCharacter (ctx context.Context, req *characterv1.GetChara
 		return &characterv1.GetCharacterResponse{}, status.Error(codes.Internal, "could not get character")
 	}
 
+	// BLOCKED: в GetCharacterResponse protos_chadnaldo v0.0.45 нет поля ника, до его появления
+	// ник передается только в заголовке ответа
+	if err := grpc.SetHeader(ctx, metadata.Pairs(nicknameHeader, characterDto.Nickname)); err != nil {
+		return nil, status.Error(codes.Internal, "could not set response header")
+	}
+
 	return &characterv1.GetCharacterResponse{
 		Name: characterDto.Name,
 		Level: int32(characterDto.CurrentLevel),
@@ -105,12 +118,36 @@ func (s *serverAPI) LevelUpCharacter (ctx context.Context, req *characterv1.Leve
 		return nil, status.Error(codes.InvalidArgument, "user id is required")
 	}
 
-	level, balance, err := s.character.LevelUpCharacter(ctx, req.UserId)
-	
+	// LevelUpCharacterRequest (protos_chadnaldo v0.0.45) не содержит способа оплаты, до обновления протоколов
+	// способ передается в метаданных запроса, а примененный возвращается в заголовке ответа
+	method := dto.PaymentMethodAuto
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(paymentMethodHeader); len(values) > 0 && values[0] != emptyValue {
+			method = values[0]
+		}
+	}
+
+	result, err := s.character.LevelUpCharacter(ctx, req.UserId, method)
 	if err != nil{
+		switch {
+		case errors.Is(err, characterservice.ErrUnknownPaymentMethod):
+			return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.InvalidArgument, "unknown payment method")
+		case errors.Is(err, characterservice.ErrNotEnoughCoins):
+			return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.FailedPrecondition, "not enough coins")
+		case errors.Is(err, characterservice.ErrNotEnoughReferrals):
+			return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.FailedPrecondition, "not enough referrals")
+		case errors.Is(err, characterservice.ErrPaymentMethodUnavailable):
+			return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.FailedPrecondition, "payment method is unavailable for this level")
+		case errors.Is(err, characterservice.ErrLevelChanged):
+			return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.Aborted, "character level was changed, try again")
+		}
 		return &characterv1.LevelUpCharacterResponse{Success: false}, status.Error(codes.Internal, "could not upgrade character level")
 	}
-	return &characterv1.LevelUpCharacterResponse{Success: true, NewLevel: int32(*level), CoinsBalance: *balance }, nil
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(paymentMethodHeader, result.PaymentMethod)); err != nil {
+		return nil, status.Error(codes.Internal, "could not set response header")
+	}
+	return &characterv1.LevelUpCharacterResponse{Success: true, NewLevel: int32(result.NewLevel), CoinsBalance: result.CoinsBalance }, nil
 }
 
 
@@ -3,6 +3,7 @@ package kafkaconsumer
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -12,9 +13,22 @@ import (
 	"github.com/segmentio/kafka-go/sasl/scram"
 )
 
+const (
+    retryMinBackoff = 500 * time.Millisecond
+    retryMaxBackoff = 30 * time.Second
+)
+
 type KafkaConsumer struct {
     reader *kafka.Reader
     logger *slog.Logger
+    characterService CharacterService
+}
+
+// CharacterService - character service methods used by event handlers
+type CharacterService interface {
+    AddMinedCoins(ctx context.Context, userID int64, claimID string, coins int64) error
+    RecordGamePlayed(ctx context.Context, userID int64) error
+    AddReferral(ctx context.Context, userID int64, referralUserID int64) error
 }
 
 
@@ -64,7 +78,7 @@ func NewKafkaReader(cfg *config.KafkaConfig) (*kafka.Reader, error) {
 	return kafka.NewReader(conf), nil
 }
 
-func NewKafkaConsumer(cfg config.KafkaConfig,  log *slog.Logger) (*KafkaConsumer, error) {
+func NewKafkaConsumer(cfg config.KafkaConfig,  log *slog.Logger, characterService CharacterService) (*KafkaConsumer, error) {
     const op = "kafka.NewKafkaConsumer"
 
     reader, err := NewKafkaReader(&cfg)
@@ -74,6 +88,7 @@ func NewKafkaConsumer(cfg config.KafkaConfig,  log *slog.Logger) (*KafkaConsumer
     return &KafkaConsumer{
         reader: reader,
         logger: log,
+        characterService: characterService,
     }, nil
 }
 
@@ -100,17 +115,52 @@ func (c *KafkaConsumer) startConsumer(ctx context.Context) error {
             c.logger.Info("Kafka consumer stopped")
             return nil
         default:
-            msg, err := c.reader.ReadMessage(ctx)
+            // Offset коммитится только после успешной обработки, иначе сбой обработчика терял бы событие
+            msg, err := c.reader.FetchMessage(ctx)
             if err != nil {
-                logger.Error("Failed to read message", "error", err)
+                if ctx.Err() != nil {
+                    continue
+                }
+                logger.Error("Failed to fetch message", "error", err)
                 continue
             }
 
-            if err := c.HandleMessage(ctx, msg.Value); err != nil {
-                logger.Error("Failed to handle message", "error", err)
-                // Здесь можно добавить логику повторных попыток или обработки ошибок
+            if err := c.handleWithRetry(ctx, msg); err != nil {
+                // Контекст отменен, сообщение будет доставлено повторно после перезапуска
+                continue
             }
+
+            if err := c.reader.CommitMessages(ctx, msg); err != nil {
+                logger.Error("Failed to commit message", "offset", msg.Offset, "error", err)
+            }
+        }
+    }
+}
+
+// handleWithRetry - handles message until success, the partition waits so the offset is not committed past it.
+// Messages that can't be decoded are skipped, retries won't fix them.
+func (c *KafkaConsumer) handleWithRetry(ctx context.Context, msg kafka.Message) error {
+    const op = "kafka.handleWithRetry"
+    logger := c.logger.With("op", op)
+
+    backoff := retryMinBackoff
+    for {
+        err := c.HandleMessage(ctx, msg.Value)
+        if err == nil {
+            return nil
+        }
+        if errors.Is(err, ErrInvalidEvent) {
+            logger.Error("Skipping invalid message", "offset", msg.Offset, "error", err)
+            return nil
+        }
+
+        logger.Error("Failed to handle message, retrying", "offset", msg.Offset, "backoff", backoff, "error", err)
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-time.After(backoff):
         }
+        backoff = min(backoff*2, retryMaxBackoff)
     }
 }
 
@@ -3,8 +3,13 @@ package kafkaconsumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 )
 
+// ErrInvalidEvent - message can't be decoded, redelivery won't fix it
+var ErrInvalidEvent = errors.New("invalid event")
+
 func (h *KafkaConsumer) HandleMessage(ctx context.Context, message []byte) error {
 	const op = "kafka.controllers.HandleMessage"
 	logger := h.logger.With("op", op);
@@ -14,12 +19,18 @@ func (h *KafkaConsumer) HandleMessage(ctx context.Context, message []byte) error
     }
     if err := json.Unmarshal(message, &event); err != nil {
         logger.Error("Failed to unmarshal event", "error", err)
-        return err
+        return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
     }
 
     switch event.Type {
     case "user_update":
         return h.HandleUserUpdateData(ctx, message)
+    case "mining_claimed":
+        return h.HandleMiningClaimed(ctx, message)
+    case "game_finished":
+        return h.HandleGameFinished(ctx, message)
+    case "referral_added":
+        return h.HandleReferralAdded(ctx, message)
     // Добавьте другие типы событий по мере необходимости
     default:
         logger.Warn("Unknown event type", "type", event.Type)
@@ -37,7 +48,7 @@ func (h *KafkaConsumer) HandleUserUpdateData(ctx context.Context, message []byte
     }
     if err := json.Unmarshal(message, &event); err != nil {
         logger.Error("Failed to unmarshal user update event", "error", err)
-        return err
+        return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
     }
 
     // err := h.userService.UpdateData(ctx, event.UserID, event.Data)
@@ -48,4 +59,70 @@ func (h *KafkaConsumer) HandleUserUpdateData(ctx context.Context, message []byte
 
     logger.Info("Successfully updated user data", "userID", event.UserID)
     return nil
-}
\ No newline at end of file
+}
+
+func (h *KafkaConsumer) HandleMiningClaimed(ctx context.Context, message []byte) error {
+	const op = "kafka.controllers.HandleMiningClaimed"
+	logger := h.logger.With("op", op);
+
+    var event struct {
+        UserID  int64  `json:"user_id"`
+        ClaimID string `json:"claim_id"` // уникален для каждой добычи, повторная доставка не начисляет монеты
+        Coins   int64  `json:"coins"`
+    }
+    if err := json.Unmarshal(message, &event); err != nil {
+        logger.Error("Failed to unmarshal mining claimed event", "error", err)
+        return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+    }
+
+    if err := h.characterService.AddMinedCoins(ctx, event.UserID, event.ClaimID, event.Coins); err != nil {
+        logger.Error("Failed to add mined coins", "error", err, "userID", event.UserID)
+        return err
+    }
+
+    logger.Info("Successfully added mined coins", "userID", event.UserID, "coins", event.Coins)
+    return nil
+}
+
+func (h *KafkaConsumer) HandleGameFinished(ctx context.Context, message []byte) error {
+	const op = "kafka.controllers.HandleGameFinished"
+	logger := h.logger.With("op", op);
+
+    var event struct {
+        UserID int64 `json:"user_id"`
+    }
+    if err := json.Unmarshal(message, &event); err != nil {
+        logger.Error("Failed to unmarshal game finished event", "error", err)
+        return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+    }
+
+    if err := h.characterService.RecordGamePlayed(ctx, event.UserID); err != nil {
+        logger.Error("Failed to record game", "error", err, "userID", event.UserID)
+        return err
+    }
+
+    logger.Info("Successfully recorded game", "userID", event.UserID)
+    return nil
+}
+
+func (h *KafkaConsumer) HandleReferralAdded(ctx context.Context, message []byte) error {
+	const op = "kafka.controllers.HandleReferralAdded"
+	logger := h.logger.With("op", op);
+
+    var event struct {
+        UserID         int64 `json:"user_id"`
+        ReferralUserID int64 `json:"referral_id"`
+    }
+    if err := json.Unmarshal(message, &event); err != nil {
+        logger.Error("Failed to unmarshal referral added event", "error", err)
+        return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+    }
+
+    if err := h.characterService.AddReferral(ctx, event.UserID, event.ReferralUserID); err != nil {
+        logger.Error("Failed to add referral", "error", err, "userID", event.UserID)
+        return err
+    }
+
+    logger.Info("Successfully added referral", "userID", event.UserID, "referralID", event.ReferralUserID)
+    return nil
+}
@@ -0,0 +1,25 @@
+package kafkaproducer
+
+import "time"
+
+const (
+	EventSkinSoldOut = "skin_sold_out"
+	EventSkinGifted = "skin_gifted"
+)
+
+// SkinSoldOutEvent - last copy of limited skin was acquired
+type SkinSoldOutEvent struct {
+	Type      string    `json:"type"`
+	SkinID    int       `json:"skin_id"`
+	MaxSupply int       `json:"max_supply"`
+	SoldOutAt time.Time `json:"sold_out_at"`
+}
+
+// SkinGiftedEvent - notification for the user who received a skin
+type SkinGiftedEvent struct {
+	Type       string    `json:"type"`
+	FromUserID int64     `json:"from_user_id"`
+	ToUserID   int64     `json:"to_user_id"`
+	SkinID     int       `json:"skin_id"`
+	GiftedAt   time.Time `json:"gifted_at"`
+}
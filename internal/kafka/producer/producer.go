@@ -1,7 +1,10 @@
 package kafkaproducer
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -48,4 +51,24 @@ func NewKafkaProducer(cfg config.KafkaConfig, log *slog.Logger) (*KafkaProducer,
 
 func (p *KafkaProducer) Close() error {
     return p.writer.Close()
-}
\ No newline at end of file
+}
+
+// SendEvent сериализует событие в JSON и отправляет его в топик
+func (p *KafkaProducer) SendEvent(ctx context.Context, key string, event interface{}) error {
+    const op = "kafka.SendEvent"
+    logger := p.logger.With("op", op)
+
+    value, err := json.Marshal(event)
+    if err != nil {
+        logger.Error("couldn't json marshal event", "error", err)
+        return fmt.Errorf("%s: %w", op, err)
+    }
+
+    err = p.writer.WriteMessages(ctx, kafka.Message{Key: []byte(key), Value: value})
+    if err != nil {
+        logger.Error("couldn't write message", "error", err)
+        return fmt.Errorf("%s: %w", op, err)
+    }
+
+    return nil
+}
@@ -0,0 +1,52 @@
+package cachekeys
+
+import "time"
+
+// Mutation - write that makes cached data stale
+type Mutation string
+
+const (
+	MutationCreated        Mutation = "created"
+	MutationLevel          Mutation = "level"
+	MutationActiveSkin     Mutation = "active_skin"
+	MutationNickname       Mutation = "nickname"
+	MutationAttributes     Mutation = "attributes"
+	MutationOwnedSkins     Mutation = "owned_skins"
+	MutationEquipment      Mutation = "equipment"
+	MutationBoosts         Mutation = "boosts"
+	MutationSkinsCatalog   Mutation = "skins_catalog"
+	MutationQuests         Mutation = "quests"
+	MutationPriceCampaigns Mutation = "price_campaigns"
+	MutationSeasons        Mutation = "seasons"
+)
+
+// IsCharacterMutation - mutation changes the character aggregate, which is rewritten instead of deleted
+func IsCharacterMutation(mutation Mutation) bool {
+	switch mutation {
+	case MutationCreated, MutationLevel, MutationActiveSkin, MutationNickname, MutationAttributes, MutationOwnedSkins,
+		MutationEquipment, MutationBoosts:
+		return true
+	}
+	return false
+}
+
+// DependentKeys - return cache keys that must be dropped after the mutation of the user data.
+// Global mutations ignore userID.
+func DependentKeys(mutation Mutation, userID int64) []string {
+	switch mutation {
+	case MutationCreated:
+		return []string{Character(userID), MissingCharacter(userID)}
+	case MutationLevel, MutationActiveSkin, MutationNickname, MutationAttributes, MutationOwnedSkins,
+		MutationEquipment, MutationBoosts:
+		return []string{Character(userID)}
+	case MutationSkinsCatalog:
+		return []string{AllSkinsInfo}
+	case MutationQuests:
+		return []string{ActiveQuests(userID, time.Now().UTC().Format(time.DateOnly))}
+	case MutationPriceCampaigns:
+		return []string{PriceCampaigns}
+	case MutationSeasons:
+		return []string{ActiveSeason}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cachekeys
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDependentKeys(t *testing.T) {
+	const userID = 42
+
+	tests := []struct {
+		mutation Mutation
+		want     []string
+	}{
+		{mutation: MutationCreated, want: []string{Character(userID), MissingCharacter(userID)}},
+		{mutation: MutationLevel, want: []string{Character(userID)}},
+		{mutation: MutationActiveSkin, want: []string{Character(userID)}},
+		{mutation: MutationNickname, want: []string{Character(userID)}},
+		{mutation: MutationAttributes, want: []string{Character(userID)}},
+		{mutation: MutationOwnedSkins, want: []string{Character(userID)}},
+		{mutation: MutationEquipment, want: []string{Character(userID)}},
+		{mutation: MutationBoosts, want: []string{Character(userID)}},
+		{mutation: MutationSkinsCatalog, want: []string{AllSkinsInfo}},
+		{mutation: MutationQuests, want: []string{ActiveQuests(userID, time.Now().UTC().Format(time.DateOnly))}},
+		{mutation: MutationPriceCampaigns, want: []string{PriceCampaigns}},
+		{mutation: MutationSeasons, want: []string{ActiveSeason}},
+		{mutation: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mutation), func(t *testing.T) {
+			if got := DependentKeys(tt.mutation, userID); !slices.Equal(got, tt.want) {
+				t.Errorf("DependentKeys(%q) = %v, want %v", tt.mutation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDependentKeysGlobalIgnoreUser(t *testing.T) {
+	for _, mutation := range []Mutation{MutationSkinsCatalog, MutationPriceCampaigns, MutationSeasons} {
+		if a, b := DependentKeys(mutation, 1), DependentKeys(mutation, 2); !slices.Equal(a, b) {
+			t.Errorf("DependentKeys(%q) depends on user: %v and %v", mutation, a, b)
+		}
+	}
+}
+
+func TestIsCharacterMutation(t *testing.T) {
+	tests := []struct {
+		mutation Mutation
+		want     bool
+	}{
+		{mutation: MutationCreated, want: true},
+		{mutation: MutationLevel, want: true},
+		{mutation: MutationActiveSkin, want: true},
+		{mutation: MutationNickname, want: true},
+		{mutation: MutationAttributes, want: true},
+		{mutation: MutationOwnedSkins, want: true},
+		{mutation: MutationEquipment, want: true},
+		{mutation: MutationBoosts, want: true},
+		{mutation: MutationSkinsCatalog},
+		{mutation: MutationQuests},
+		{mutation: MutationPriceCampaigns},
+		{mutation: MutationSeasons},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mutation), func(t *testing.T) {
+			if got := IsCharacterMutation(tt.mutation); got != tt.want {
+				t.Errorf("IsCharacterMutation(%q) = %v, want %v", tt.mutation, got, tt.want)
+			}
+			// Агрегат сбрасывают только мутации персонажа
+			dropsAggregate := slices.Contains(DependentKeys(tt.mutation, 1), Character(1))
+			if dropsAggregate != tt.want {
+				t.Errorf("DependentKeys(%q) contains character aggregate = %v, want %v", tt.mutation, dropsAggregate, tt.want)
+			}
+		})
+	}
+}
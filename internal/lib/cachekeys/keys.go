@@ -1,20 +1,99 @@
 package cachekeys
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
+
+// Schema versions of cached objects. Version is the key prefix, so after a DTO change
+// bump the version of its key class and instances with the old DTO keep reading the old keys.
+const (
+	characterVersion = "v2:"
+	skinsInfoVersion = "v1:"
+	levelPricesVersion = "v1:"
+	priceCampaignsVersion = "v1:"
+	activeSeasonVersion = "v2:"
+	activeQuestsVersion = "v1:"
+)
 
 const (
-	CharacterLevelPrefix = "character_level:"
-	CharacterDataPrefix = "character_data:"
+	CharacterPrefix = characterVersion + "character:"
+	MissingCharacterPrefix = "missing_character:"
 
-	AllSkinsInfo = "skins_info"
-	LevelPrices = "level_prices"
+	AllSkinsInfo = skinsInfoVersion + "skins_info"
+	LevelPrices = levelPricesVersion + "level_prices"
+	PriceCampaigns = priceCampaignsVersion + "price_campaigns"
+	ActiveSeason = activeSeasonVersion + "active_season"
+	SkinGiftsDailyPrefix = "skin_gifts_daily:"
+	ActiveQuestsPrefix = activeQuestsVersion + "active_quests:"
+	QuestsAssignedPrefix = "quests_assigned:"
+	FraudPrefix = "fraud:"
+
+	LeaderboardLevel = "leaderboard:level"
+	LeaderboardMining = "leaderboard:mining"
+	// RecentlyActive - sorted set of users by the time of the last character read
+	RecentlyActive = "recently_active"
 )
 
-// CharacterLevel - return generated key from prefix const and user id
-func CharacterLevel(userID int64) string {
-    return fmt.Sprintf("%s%d", CharacterLevelPrefix, userID)
+var versionPrefix = regexp.MustCompile(`^v[0-9]+:`)
+
+// Class - return key without schema version, key classes in config are set without versions
+func Class(key string) string {
+	return versionPrefix.ReplaceAllString(key, "")
+}
+
+// Character - return key of the character aggregate of the user
+func Character(userID int64) string {
+	return fmt.Sprintf("%s%d", CharacterPrefix, userID)
+}
+
+// MissingCharacter - return key of the marker of the user without character
+func MissingCharacter(userID int64) string {
+	return fmt.Sprintf("%s%d", MissingCharacterPrefix, userID)
+}
+
+// LoadLock - return key of the lock held while the missed key is loaded from the database
+func LoadLock(key string) string {
+	return "lock:" + key
+}
+
+// LeaderboardRebuild - return temporary key used while leaderboard is rebuilt
+func LeaderboardRebuild(board string) string {
+	return board + ":rebuild"
 }
 
-func CharacterData(userID int64) string {
-	return fmt.Sprintf("%s%d", CharacterDataPrefix, userID)
-}
\ No newline at end of file
+// LeaderboardRebuilding - return key of the marker set while leaderboard is rebuilt
+func LeaderboardRebuilding(board string) string {
+	return board + ":rebuilding"
+}
+
+
+// SkinGiftsDaily - return key of the gifts counter of the user for the day
+func SkinGiftsDaily(userID int64, day string) string {
+	return fmt.Sprintf("%s%d:%s", SkinGiftsDailyPrefix, userID, day)
+}
+
+// ActiveQuests - return key of the quests list of the user for the day
+func ActiveQuests(userID int64, day string) string {
+	return fmt.Sprintf("%s%d:%s", ActiveQuestsPrefix, userID, day)
+}
+
+// QuestsAssigned - return key of the marker set when quests of the user are assigned for the day
+func QuestsAssigned(userID int64, day string) string {
+	return fmt.Sprintf("%s%d:%s", QuestsAssignedPrefix, userID, day)
+}
+
+// FraudOperations - return key of the sliding window of user operations
+func FraudOperations(userID int64, operation string) string {
+	return fmt.Sprintf("%sops:%s:%d", FraudPrefix, operation, userID)
+}
+
+// FraudRollbacks - return key of the sliding window of user payment rollbacks
+func FraudRollbacks(userID int64) string {
+	return fmt.Sprintf("%srollbacks:%d", FraudPrefix, userID)
+}
+
+// FraudReferrals - return key of the referrals count seen on the previous check
+func FraudReferrals(userID int64) string {
+	return fmt.Sprintf("%sreferrals:%d", FraudPrefix, userID)
+}
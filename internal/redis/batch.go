@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GetMany декодирует найденные ключи в dests одним запросом и возвращает их, отсутствующие ключи не считаются ошибкой
+func (r *RedisCache) GetMany(ctx context.Context, dests map[string]interface{}) ([]string, error) {
+	const op = "redis.getMany"
+	logger := r.logger.With("op", op)
+
+	if len(dests) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(dests))
+	for key := range dests {
+		keys = append(keys, key)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		logger.Error("couldn't get values", "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	found := make([]string, 0, len(keys))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if err := r.decode(ctx, keys[i], []byte(data), dests[keys[i]]); err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		found = append(found, keys[i])
+	}
+	return found, nil
+}
+
+// SetMany сохраняет объекты одним запросом с общим временем жизни
+func (r *RedisCache) SetMany(ctx context.Context, values map[string]interface{}, expiration time.Duration) error {
+	const op = "redis.setMany"
+	logger := r.logger.With("op", op)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := r.client.TxPipeline()
+	for key, value := range values {
+		data, err := r.codec.Encode(key, value)
+		if err != nil {
+			logger.Error("couldn't encode value", "key", key, "error", err)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		pipe.Set(ctx, key, data, expiration)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("couldn't set values", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteMany удаляет ключи одним запросом
+func (r *RedisCache) DeleteMany(ctx context.Context, keys ...string) error {
+	const op = "redis.deleteMany"
+	logger := r.logger.With("op", op)
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		logger.Error("couldn't delete values", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+)
+
+// Payload formats, the first byte of the encoded value
+const (
+	formatJSON byte = 'j'
+	formatGob  byte = 'g'
+)
+
+// headerSize - format byte and schema fingerprint
+const headerSize = 5
+
+// ErrSchemaMismatch - cached value was encoded from another version of the type or by old code
+var ErrSchemaMismatch = errors.New("cached value schema mismatch")
+
+// Codec - encodes cached objects with a header of format and schema fingerprint of the type.
+// Fingerprint covers field names, tags and kinds, so value cached before a DTO change is discarded on read
+// even when the key version was not bumped. Format is read from the header, so changing
+// binary key classes does not break entries that are already cached.
+type Codec struct {
+	binaryClasses []string
+}
+
+// schemas - fingerprints by type, computed once
+var schemas sync.Map
+
+// NewCodec - keys of the binary classes are encoded with gob, others with JSON
+func NewCodec(binaryClasses []string) *Codec {
+	return &Codec{binaryClasses: binaryClasses}
+}
+
+// Encode кодирует значение для ключа с заголовком формата и схемы
+func (c *Codec) Encode(key string, value interface{}) ([]byte, error) {
+	format := formatJSON
+	if c.isBinary(key) {
+		format = formatGob
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(format)
+	binary.Write(&buf, binary.BigEndian, schemaOf(reflect.TypeOf(value)))
+
+	var err error
+	switch format {
+	case formatGob:
+		// gob не кодирует nil на верхнем уровне, такие значения остаются в JSON
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			buf.Bytes()[0] = formatJSON
+			err = json.NewEncoder(&buf).Encode(value)
+		} else {
+			err = gob.NewEncoder(&buf).Encode(value)
+		}
+	default:
+		err = json.NewEncoder(&buf).Encode(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode декодирует значение, ErrSchemaMismatch если схема типа не совпадает
+func (c *Codec) Decode(data []byte, dest interface{}) error {
+	if len(data) < headerSize {
+		return ErrSchemaMismatch
+	}
+	if binary.BigEndian.Uint32(data[1:headerSize]) != schemaOf(reflect.TypeOf(dest)) {
+		return ErrSchemaMismatch
+	}
+
+	body := data[headerSize:]
+	switch data[0] {
+	case formatJSON:
+		return json.Unmarshal(body, dest)
+	case formatGob:
+		return gob.NewDecoder(bytes.NewReader(body)).Decode(dest)
+	default:
+		return ErrSchemaMismatch
+	}
+}
+
+func (c *Codec) isBinary(key string) bool {
+	class := cachekeys.Class(key)
+	for _, prefix := range c.binaryClasses {
+		if strings.HasPrefix(class, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaOf - fingerprint of the type structure, pointers are ignored so value and destination match
+func schemaOf(t reflect.Type) uint32 {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return 0
+	}
+	if schema, ok := schemas.Load(t); ok {
+		return schema.(uint32)
+	}
+
+	h := fnv.New32a()
+	writeSchema(h, t, map[reflect.Type]bool{})
+	schema := h.Sum32()
+	schemas.Store(t, schema)
+	return schema
+}
+
+func writeSchema(w interface{ Write([]byte) (int, error) }, t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fmt.Fprintf(w, "%s:%s;", t.PkgPath()+"."+t.Name(), t.Kind())
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fmt.Fprintf(w, "%s`%s`{", field.Name, field.Tag.Get("json"))
+			writeSchema(w, field.Type, seen)
+			fmt.Fprint(w, "}")
+		}
+	case reflect.Slice, reflect.Array:
+		writeSchema(w, t.Elem(), seen)
+	case reflect.Map:
+		writeSchema(w, t.Key(), seen)
+		writeSchema(w, t.Elem(), seen)
+	}
+}
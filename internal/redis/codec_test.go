@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type codecItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type codecPayload struct {
+	Title string      `json:"title"`
+	Items []codecItem `json:"items"`
+	Tags  map[string]int
+}
+
+// codecPayloadRenamed - codecPayload after a field rename
+type codecPayloadRenamed struct {
+	Title string      `json:"title"`
+	Items []codecItem `json:"entries"`
+	Tags  map[string]int
+}
+
+// codecPayloadRetyped - codecPayload after a field type change
+type codecPayloadRetyped struct {
+	Title string      `json:"title"`
+	Items []codecItem `json:"items"`
+	Tags  map[string]string
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	payload := codecPayload{
+		Title: "catalog",
+		Items: []codecItem{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}},
+		Tags:  map[string]int{"a": 1},
+	}
+	codec := NewCodec([]string{"binary:"})
+
+	tests := []struct {
+		name       string
+		key        string
+		wantFormat byte
+	}{
+		{name: "json", key: "v1:plain:1", wantFormat: formatJSON},
+		{name: "gob for binary class", key: "binary:1", wantFormat: formatGob},
+		{name: "class ignores key version", key: "v3:binary:1", wantFormat: formatGob},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := codec.Encode(tt.key, payload)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if data[0] != tt.wantFormat {
+				t.Errorf("Encode() format = %q, want %q", data[0], tt.wantFormat)
+			}
+
+			var got codecPayload
+			if err := codec.Decode(data, &got); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, payload) {
+				t.Errorf("Decode() = %+v, want %+v", got, payload)
+			}
+		})
+	}
+}
+
+func TestCodecFormatIsReadFromHeader(t *testing.T) {
+	payload := codecItem{ID: 1, Name: "first"}
+
+	data, err := NewCodec([]string{"binary:"}).Encode("binary:1", payload)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// Класс ключа больше не бинарный, но уже закэшированное значение читается
+	var got codecItem
+	if err := NewCodec(nil).Decode(data, &got); err != nil || got != payload {
+		t.Errorf("Decode() = %+v, %v, want %+v", got, err, payload)
+	}
+}
+
+func TestCodecNilPointerInBinaryClass(t *testing.T) {
+	codec := NewCodec([]string{"binary:"})
+
+	data, err := codec.Encode("binary:1", (*codecItem)(nil))
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if data[0] != formatJSON {
+		t.Errorf("Encode() format = %q, want %q", data[0], formatJSON)
+	}
+
+	got := &codecItem{ID: 1}
+	if err := codec.Decode(data, &got); err != nil || got != nil {
+		t.Errorf("Decode() = %+v, %v, want nil", got, err)
+	}
+}
+
+func TestCodecSchemaMismatch(t *testing.T) {
+	codec := NewCodec(nil)
+	data, err := codec.Encode("v1:plain:1", codecPayload{Title: "catalog"})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		dest interface{}
+	}{
+		{name: "renamed field", data: data, dest: &codecPayloadRenamed{}},
+		{name: "changed field type", data: data, dest: &codecPayloadRetyped{}},
+		{name: "other type", data: data, dest: &codecItem{}},
+		{name: "value without header", data: []byte(`{}`), dest: &codecPayload{}},
+		{name: "unknown format", data: append([]byte{'x'}, data[1:]...), dest: &codecPayload{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := codec.Decode(tt.data, tt.dest); !errors.Is(err, ErrSchemaMismatch) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrSchemaMismatch)
+			}
+		})
+	}
+}
+
+func TestSchemaOfIgnoresPointers(t *testing.T) {
+	value := schemaOf(reflect.TypeOf(codecItem{}))
+	if pointer := schemaOf(reflect.TypeOf(&codecItem{})); pointer != value {
+		t.Errorf("schemaOf(*T) = %d, want schemaOf(T) = %d", pointer, value)
+	}
+	if other := schemaOf(reflect.TypeOf(codecPayload{})); other == value {
+		t.Errorf("schemaOf() is equal for different types")
+	}
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// InvalidationChannel - pub/sub канал, через который инстансы сообщают об удаленных ключах
+const InvalidationChannel = "cache:invalidations"
+
+// InvalidationMessage - ключи, удаленные инстансом Source
+type InvalidationMessage struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys"`
+}
+
+// instanceID - идентификатор процесса, чтобы не обрабатывать собственные инвалидации
+var instanceID = uuid.New().String()
+
+// Invalidate удаляет ключи и рассылает инвалидацию остальным инстансам
+func (r *RedisCache) Invalidate(ctx context.Context, keys ...string) error {
+	const op = "redis.invalidate"
+	logger := r.logger.With("op", op)
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(InvalidationMessage{Source: instanceID, Keys: keys})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, keys...)
+	pipe.Publish(ctx, InvalidationChannel, payload)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("couldn't invalidate keys", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SubscribeInvalidations вызывает handler для инвалидаций других инстансов, пока не отменен ctx
+func (r *RedisCache) SubscribeInvalidations(ctx context.Context, handler func(keys []string)) error {
+	const op = "redis.subscribeInvalidations"
+	logger := r.logger.With("op", op)
+
+	pubsub := r.client.Subscribe(ctx, InvalidationChannel)
+	defer pubsub.Close()
+
+	// Дожидаемся подтверждения подписки, чтобы не потерять инвалидации сразу после старта
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var invalidation InvalidationMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+				logger.Error("couldn't unmarshal invalidation", "error", err)
+				continue
+			}
+			if invalidation.Source == instanceID {
+				continue
+			}
+			handler(invalidation.Keys)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+)
+
+// LayeredCache - Redis with in-process LRU in front of it for configured key classes.
+// Local entries of other instances are dropped through pub/sub invalidations, see Run.
+// All other Redis operations go straight to RedisCache.
+type LayeredCache struct {
+	*RedisCache
+	local   *lru
+	classes []config.LocalKeyClass
+}
+
+// NewLayeredCache - wraps Redis cache, local tier is disabled when cfg size is zero
+func NewLayeredCache(redisCache *RedisCache, cfg config.LocalCacheConfig) *LayeredCache {
+	layered := &LayeredCache{RedisCache: redisCache}
+	if cfg.Size > 0 && len(cfg.KeyClasses) > 0 {
+		layered.local = newLRU(cfg.Size)
+		layered.classes = cfg.KeyClasses
+	}
+	return layered
+}
+
+// Run слушает инвалидации других инстансов и удаляет ключи из локального уровня, пока не отменен ctx
+func (l *LayeredCache) Run(ctx context.Context) error {
+	if l.local == nil {
+		return nil
+	}
+	return l.SubscribeInvalidations(ctx, func(keys []string) {
+		l.local.delete(keys...)
+	})
+}
+
+// Get читает ключ из локального уровня, при промахе из Redis с сохранением в локальный уровень
+func (l *LayeredCache) Get(ctx context.Context, key string, dest interface{}) error {
+	ttl, ok := l.localTTL(key)
+	if !ok {
+		return l.RedisCache.Get(ctx, key, dest)
+	}
+
+	var data []byte
+	if local, found := l.local.get(key); found {
+		data = local.([]byte)
+	} else {
+		var err error
+		data, err = l.getBytes(ctx, key)
+		if err != nil {
+			return err
+		}
+		// Время жизни в Redis неизвестно, поэтому локальная копия живет не дольше TTL класса
+		l.local.set(key, data, ttl)
+	}
+
+	if err := l.decode(ctx, key, data, dest); err != nil {
+		l.local.delete(key)
+		return err
+	}
+	return nil
+}
+
+// Set сохраняет объект в Redis и в локальный уровень.
+// Другие инстансы не уведомляются, изменение данных должно сопровождаться Invalidate.
+func (l *LayeredCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	const op = "redis.layered.set"
+
+	ttl, ok := l.localTTL(key)
+	if !ok {
+		return l.RedisCache.Set(ctx, key, value, expiration)
+	}
+
+	data, err := l.codec.Encode(key, value)
+	if err != nil {
+		l.logger.Error("couldn't encode value", "op", op, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := l.setBytes(ctx, key, data, expiration); err != nil {
+		l.local.delete(key)
+		return err
+	}
+
+	if expiration > 0 {
+		ttl = min(ttl, expiration)
+	}
+	l.local.set(key, data, ttl)
+	return nil
+}
+
+// Delete удаляет ключ, для локальных ключей рассылает инвалидацию другим инстансам
+func (l *LayeredCache) Delete(ctx context.Context, key string) error {
+	if _, ok := l.localTTL(key); ok {
+		return l.Invalidate(ctx, key)
+	}
+	return l.RedisCache.Delete(ctx, key)
+}
+
+// SetMany сохраняет объекты в Redis, локальные копии удаляются и загружаются при следующем чтении
+func (l *LayeredCache) SetMany(ctx context.Context, values map[string]interface{}, expiration time.Duration) error {
+	if l.local != nil {
+		for key := range values {
+			l.local.delete(key)
+		}
+	}
+	return l.RedisCache.SetMany(ctx, values, expiration)
+}
+
+// DeleteMany удаляет ключи, для локальных ключей рассылает инвалидацию другим инстансам
+func (l *LayeredCache) DeleteMany(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if _, ok := l.localTTL(key); ok {
+			return l.Invalidate(ctx, keys...)
+		}
+	}
+	return l.RedisCache.DeleteMany(ctx, keys...)
+}
+
+// Invalidate удаляет ключи из обоих уровней и рассылает инвалидацию другим инстансам
+func (l *LayeredCache) Invalidate(ctx context.Context, keys ...string) error {
+	if l.local != nil {
+		l.local.delete(keys...)
+	}
+	return l.RedisCache.Invalidate(ctx, keys...)
+}
+
+// localTTL - returns local TTL of the key class, false when key is not kept locally
+func (l *LayeredCache) localTTL(key string) (time.Duration, bool) {
+	if l.local == nil {
+		return 0, false
+	}
+	keyClass := cachekeys.Class(key)
+	for _, class := range l.classes {
+		if strings.HasPrefix(keyClass, class.Prefix) && class.TTL > 0 {
+			return class.TTL, true
+		}
+	}
+	return 0, false
+}
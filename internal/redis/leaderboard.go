@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ZSetMember - member of sorted set with its score
+type ZSetMember struct {
+	Member int64
+	Score  float64
+}
+
+// ZAdd сохраняет элементы в отсортированное множество
+func (r *RedisCache) ZAdd(ctx context.Context, key string, members ...ZSetMember) error {
+	const op = "redis.zAdd"
+	logger := r.logger.With("op", op)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	zMembers := make([]redis.Z, len(members))
+	for i, m := range members {
+		zMembers[i] = redis.Z{Score: m.Score, Member: m.Member}
+	}
+
+	if err := r.client.ZAdd(ctx, key, zMembers...).Err(); err != nil {
+		logger.Error("couldn't add members", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ZAddGT добавляет элементы, существующим элементам score меняется только на больший
+func (r *RedisCache) ZAddGT(ctx context.Context, key string, members ...ZSetMember) error {
+	const op = "redis.zAddGT"
+	logger := r.logger.With("op", op)
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	zMembers := make([]redis.Z, len(members))
+	for i, m := range members {
+		zMembers[i] = redis.Z{Score: m.Score, Member: m.Member}
+	}
+
+	if err := r.client.ZAddGT(ctx, key, zMembers...).Err(); err != nil {
+		logger.Error("couldn't add members", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ZRevRange возвращает элементы множества по убыванию score
+func (r *RedisCache) ZRevRange(ctx context.Context, key string, start, stop int64) ([]ZSetMember, error) {
+	const op = "redis.zRevRange"
+	logger := r.logger.With("op", op)
+
+	zMembers, err := r.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		logger.Error("couldn't get range", "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members := make([]ZSetMember, 0, len(zMembers))
+	for _, z := range zMembers {
+		member, err := strconv.ParseInt(fmt.Sprint(z.Member), 10, 64)
+		if err != nil {
+			logger.Error("couldn't parse member", "member", z.Member, "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, ZSetMember{Member: member, Score: z.Score})
+	}
+	return members, nil
+}
+
+// ZRevRank возвращает позицию (с нуля) и score элемента по убыванию score
+func (r *RedisCache) ZRevRank(ctx context.Context, key string, member int64) (*int64, *float64, error) {
+	const op = "redis.zRevRank"
+	logger := r.logger.With("op", op)
+
+	res, err := r.client.ZRevRankWithScore(ctx, key, strconv.FormatInt(member, 10)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			logger.Debug("member not found", "key", key, "member", member)
+			return nil, nil, fmt.Errorf("%s: member not found: %w", op, err)
+		}
+		logger.Error("couldn't get rank", "error", err)
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &res.Rank, &res.Score, nil
+}
+
+// ZTrim оставляет в множестве keep элементов с наибольшим score
+func (r *RedisCache) ZTrim(ctx context.Context, key string, keep int64) error {
+	const op = "redis.zTrim"
+	logger := r.logger.With("op", op)
+
+	if err := r.client.ZRemRangeByRank(ctx, key, 0, -keep-1).Err(); err != nil {
+		logger.Error("couldn't trim set", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Rename атомарно заменяет ключ newKey содержимым key
+func (r *RedisCache) Rename(ctx context.Context, key, newKey string) error {
+	const op = "redis.rename"
+	logger := r.logger.With("op", op)
+
+	if err := r.client.Rename(ctx, key, newKey).Err(); err != nil {
+		logger.Error("couldn't rename key", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript удаляет блокировку, только если она все еще принадлежит владельцу токена
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock пытается захватить блокировку на ttl, возвращает токен владельца или пустую строку, если она занята
+func (r *RedisCache) Lock(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	const op = "redis.lock"
+	logger := r.logger.With("op", op)
+
+	token := uuid.New().String()
+	acquired, err := r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		logger.Error("couldn't acquire lock", "error", err)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if !acquired {
+		return "", nil
+	}
+	return token, nil
+}
+
+// Unlock освобождает блокировку, захваченную с токеном
+func (r *RedisCache) Unlock(ctx context.Context, key string, token string) error {
+	const op = "redis.unlock"
+	logger := r.logger.With("op", op)
+
+	if err := unlockScript.Run(ctx, r.client, []string{key}, token).Err(); err != nil {
+		logger.Error("couldn't release lock", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	type step struct {
+		op    string // set, expired, get, delete
+		key   string
+		value string
+		ttl   time.Duration
+		want  bool // get: key is found
+	}
+
+	tests := []struct {
+		name  string
+		size  int
+		steps []step
+	}{
+		{
+			name: "set and get",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "get", key: "a", value: "1", want: true},
+				{op: "get", key: "b"},
+			},
+		},
+		{
+			name: "overwrite",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "a", value: "2"},
+				{op: "get", key: "a", value: "2", want: true},
+			},
+		},
+		{
+			name: "evicts least recently set",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "a"},
+				{op: "get", key: "b", value: "2", want: true},
+				{op: "get", key: "c", value: "3", want: true},
+			},
+		},
+		{
+			name: "get refreshes recency",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "get", key: "a", value: "1", want: true},
+				{op: "set", key: "c", value: "3"},
+				{op: "get", key: "b"},
+				{op: "get", key: "a", value: "1", want: true},
+			},
+		},
+		{
+			name: "overwrite refreshes recency",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "set", key: "a", value: "3"},
+				{op: "set", key: "c", value: "4"},
+				{op: "get", key: "b"},
+				{op: "get", key: "a", value: "3", want: true},
+			},
+		},
+		{
+			name: "expired entry is missing",
+			size: 2,
+			steps: []step{
+				{op: "expired", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2", ttl: time.Hour},
+				{op: "get", key: "a"},
+				{op: "get", key: "b", value: "2", want: true},
+			},
+		},
+		{
+			name: "delete",
+			size: 2,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "set", key: "b", value: "2"},
+				{op: "delete", key: "a"},
+				{op: "delete", key: "missing"},
+				{op: "get", key: "a"},
+				{op: "get", key: "b", value: "2", want: true},
+			},
+		},
+		{
+			name: "zero size keeps nothing",
+			size: 0,
+			steps: []step{
+				{op: "set", key: "a", value: "1"},
+				{op: "get", key: "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLRU(tt.size)
+			for i, s := range tt.steps {
+				switch s.op {
+				case "set":
+					l.set(s.key, s.value, s.ttl)
+				case "expired":
+					l.setUntil(s.key, s.value, time.Now().Add(-time.Second))
+				case "delete":
+					l.delete(s.key)
+				case "get":
+					value, ok := l.get(s.key)
+					if ok != s.want {
+						t.Fatalf("step %d: get(%q) found = %v, want %v", i, s.key, ok, s.want)
+					}
+					if ok && value != s.value {
+						t.Fatalf("step %d: get(%q) = %v, want %v", i, s.key, value, s.value)
+					}
+				}
+			}
+			if l.order.Len() != len(l.entries) || l.order.Len() > tt.size {
+				t.Errorf("inconsistent lru: %d in order, %d in index, size %d", l.order.Len(), len(l.entries), tt.size)
+			}
+		})
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	l := newLRU(1)
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	l.setUntil("a", "1", expiresAt)
+
+	_, got, ok := l.getWithExpiry("a")
+	if !ok || !got.Equal(expiresAt) {
+		t.Errorf("getWithExpiry() = %v, %v, want %v, true", got, ok, expiresAt)
+	}
+
+	l.set("b", "2", 0)
+	if _, got, ok := l.getWithExpiry("b"); !ok || !got.IsZero() {
+		t.Errorf("getWithExpiry() of entry without ttl = %v, %v, want zero time, true", got, ok)
+	}
+}
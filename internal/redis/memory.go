@@ -0,0 +1,526 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// errWrongType - operation is applied to key holding another kind of value
+var errWrongType = errors.New("operation against a key holding the wrong kind of value")
+
+// MemoryCache - in-process cache with the same semantics as RedisCache, for local development and tests.
+// Missing keys are reported with redis.Nil, so callers do not depend on the backend.
+// Entries expire lazily on access, the least recently used entry is evicted when the cache is full.
+type MemoryCache struct {
+	// mu - serializes read-modify-write operations, lru guards only single reads and writes
+	mu      sync.Mutex
+	entries *lru
+	codec   *Codec
+}
+
+// memoryZSet - sorted set, member to score
+type memoryZSet map[int64]float64
+
+// memoryVersioned - value saved with SetVersioned
+type memoryVersioned struct {
+	version int64
+	data    []byte
+}
+
+func NewMemoryCache(cfg config.MemoryCacheConfig) *MemoryCache {
+	return &MemoryCache{entries: newLRU(cfg.Size), codec: NewCodec(nil)}
+}
+
+func (m *MemoryCache) SetString(_ context.Context, key string, value string, expiration time.Duration) error {
+	m.entries.set(key, []byte(value), expiration)
+	return nil
+}
+
+func (m *MemoryCache) GetString(_ context.Context, key string) (*string, error) {
+	const op = "memory.getString"
+
+	data, err := m.getBytes(op, key)
+	val := string(data)
+	return &val, err
+}
+
+func (m *MemoryCache) GetInt(_ context.Context, key string) (*int, error) {
+	const op = "memory.getInt"
+
+	data, err := m.getBytes(op, key)
+	if err != nil {
+		return new(int), err
+	}
+
+	val, err := strconv.Atoi(string(data))
+	if err != nil {
+		return &val, fmt.Errorf("%s: %w", op, err)
+	}
+	return &val, nil
+}
+
+func (m *MemoryCache) SetInt(_ context.Context, key string, value int, expiration time.Duration) error {
+	m.entries.set(key, []byte(strconv.Itoa(value)), expiration)
+	return nil
+}
+
+// Set сохраняет любой объект в кэше
+func (m *MemoryCache) Set(_ context.Context, key string, value interface{}, expiration time.Duration) error {
+	const op = "memory.set"
+
+	data, err := m.codec.Encode(key, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.entries.set(key, data, expiration)
+	return nil
+}
+
+// Get получает объект из кэша и десериализует его в указанный тип
+func (m *MemoryCache) Get(_ context.Context, key string, dest interface{}) error {
+	const op = "memory.get"
+
+	data, err := m.getBytes(op, key)
+	if err != nil {
+		return err
+	}
+
+	err = m.codec.Decode(data, dest)
+	if errors.Is(err, ErrSchemaMismatch) {
+		m.entries.delete(key)
+		return fmt.Errorf("%s: %w: %w", op, err, redis.Nil)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetMany декодирует найденные ключи в dests и возвращает их
+func (m *MemoryCache) GetMany(ctx context.Context, dests map[string]interface{}) ([]string, error) {
+	found := make([]string, 0, len(dests))
+	for key, dest := range dests {
+		if err := m.Get(ctx, key, dest); err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, err
+		}
+		found = append(found, key)
+	}
+	return found, nil
+}
+
+// SetMany сохраняет объекты с общим временем жизни
+func (m *MemoryCache) SetMany(_ context.Context, values map[string]interface{}, expiration time.Duration) error {
+	const op = "memory.setMany"
+
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := m.codec.Encode(key, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		encoded[key] = data
+	}
+
+	for key, data := range encoded {
+		m.entries.set(key, data, expiration)
+	}
+	return nil
+}
+
+// GetVersioned получает объект, сохраненный через SetVersioned, и возвращает его версию
+func (m *MemoryCache) GetVersioned(_ context.Context, key string, dest interface{}) (int64, error) {
+	const op = "memory.getVersioned"
+
+	value, ok := m.entries.get(key)
+	if !ok {
+		return 0, fmt.Errorf("%s: key not found: %w", op, redis.Nil)
+	}
+	versioned, ok := value.(memoryVersioned)
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", op, errWrongType)
+	}
+
+	err := m.codec.Decode(versioned.data, dest)
+	if errors.Is(err, ErrSchemaMismatch) {
+		m.entries.delete(key)
+		return 0, fmt.Errorf("%s: %w: %w", op, err, redis.Nil)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return versioned.version, nil
+}
+
+// SetVersioned сохраняет объект, если в кэше нет версии новее или равной, возвращает true если значение сохранено
+func (m *MemoryCache) SetVersioned(_ context.Context, key string, version int64, value interface{}, expiration time.Duration) (bool, error) {
+	const op = "memory.setVersioned"
+
+	data, err := m.codec.Encode(key, value)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.entries.get(key); ok {
+		if versioned, ok := current.(memoryVersioned); ok && versioned.version >= version {
+			return false, nil
+		}
+	}
+
+	m.entries.set(key, memoryVersioned{version: version, data: data}, expiration)
+	return true, nil
+}
+
+// Exists проверяет наличие ключа в кэше
+func (m *MemoryCache) Exists(_ context.Context, key string) (*int64, error) {
+	var count int64
+	if _, ok := m.entries.get(key); ok {
+		count = 1
+	}
+	return &count, nil
+}
+
+// Delete удаляет ключ из кэша
+func (m *MemoryCache) Delete(_ context.Context, key string) error {
+	m.entries.delete(key)
+	return nil
+}
+
+// DeleteMany удаляет ключи из кэша
+func (m *MemoryCache) DeleteMany(_ context.Context, keys ...string) error {
+	m.entries.delete(keys...)
+	return nil
+}
+
+// Invalidate удаляет ключи, других инстансов у кэша в памяти нет
+func (m *MemoryCache) Invalidate(_ context.Context, keys ...string) error {
+	m.entries.delete(keys...)
+	return nil
+}
+
+// Incr увеличивает счетчик и задает время жизни при его создании
+func (m *MemoryCache) Incr(_ context.Context, key string, expiration time.Duration) (int64, error) {
+	const op = "memory.incr"
+	return m.addInt(op, key, 1, expiration)
+}
+
+// Decr уменьшает счетчик
+func (m *MemoryCache) Decr(_ context.Context, key string) error {
+	const op = "memory.decr"
+	_, err := m.addInt(op, key, -1, 0)
+	return err
+}
+
+// SwapInt сохраняет новое значение и возвращает предыдущее, nil если его не было
+func (m *MemoryCache) SwapInt(_ context.Context, key string, value int, expiration time.Duration) (*int, error) {
+	const op = "memory.swapInt"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var prev *int
+	if data, err := m.getBytes(op, key); err == nil {
+		val, err := strconv.Atoi(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		prev = &val
+	}
+
+	m.entries.set(key, []byte(strconv.Itoa(value)), expiration)
+	return prev, nil
+}
+
+// Lock пытается захватить блокировку на ttl, возвращает токен владельца или пустую строку, если она занята
+func (m *MemoryCache) Lock(_ context.Context, key string, ttl time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries.get(key); ok {
+		return "", nil
+	}
+
+	token := uuid.New().String()
+	m.entries.set(key, []byte(token), ttl)
+	return token, nil
+}
+
+// Unlock освобождает блокировку, захваченную с токеном
+func (m *MemoryCache) Unlock(_ context.Context, key string, token string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if value, ok := m.entries.get(key); ok {
+		if data, ok := value.([]byte); ok && string(data) == token {
+			m.entries.delete(key)
+		}
+	}
+	return nil
+}
+
+// WindowAdd добавляет событие в скользящее окно и возвращает число событий в окне вместе с ним
+func (m *MemoryCache) WindowAdd(_ context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	const op = "memory.windowAdd"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, _, err := m.getZSet(op, key)
+	if err != nil {
+		return 0, err
+	}
+
+	from := float64(now.Add(-window).UnixNano())
+	for member, score := range zset {
+		if score <= from {
+			delete(zset, member)
+		}
+	}
+	zset[now.UnixNano()] = float64(now.UnixNano())
+
+	m.entries.set(key, zset, window)
+	return int64(len(zset)), nil
+}
+
+// WindowCount возвращает число событий в скользящем окне
+func (m *MemoryCache) WindowCount(_ context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	const op = "memory.windowCount"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, _, err := m.getZSet(op, key)
+	if err != nil {
+		return 0, err
+	}
+
+	from, to := float64(now.Add(-window).UnixNano()), float64(now.UnixNano())
+	var count int64
+	for _, score := range zset {
+		if score >= from && score <= to {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ZAdd сохраняет элементы в отсортированное множество
+func (m *MemoryCache) ZAdd(_ context.Context, key string, members ...ZSetMember) error {
+	const op = "memory.zAdd"
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, expiresAt, err := m.getZSet(op, key)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		zset[member.Member] = member.Score
+	}
+
+	m.entries.setUntil(key, zset, expiresAt)
+	return nil
+}
+
+// ZAddGT добавляет элементы, существующим элементам score меняется только на больший
+func (m *MemoryCache) ZAddGT(_ context.Context, key string, members ...ZSetMember) error {
+	const op = "memory.zAddGT"
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, expiresAt, err := m.getZSet(op, key)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if score, ok := zset[member.Member]; !ok || member.Score > score {
+			zset[member.Member] = member.Score
+		}
+	}
+
+	m.entries.setUntil(key, zset, expiresAt)
+	return nil
+}
+
+// ZRevRange возвращает элементы множества по убыванию score, индексы как в Redis
+func (m *MemoryCache) ZRevRange(_ context.Context, key string, start, stop int64) ([]ZSetMember, error) {
+	const op = "memory.zRevRange"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, _, err := m.getZSet(op, key)
+	if err != nil {
+		return nil, err
+	}
+
+	members := sortedRev(zset)
+	size := int64(len(members))
+	if start < 0 {
+		start = max(size+start, 0)
+	}
+	if stop < 0 {
+		stop = size + stop
+	}
+	stop = min(stop, size-1)
+	if start > stop {
+		return []ZSetMember{}, nil
+	}
+	return members[start : stop+1], nil
+}
+
+// ZRevRank возвращает позицию (с нуля) и score элемента по убыванию score
+func (m *MemoryCache) ZRevRank(_ context.Context, key string, member int64) (*int64, *float64, error) {
+	const op = "memory.zRevRank"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, _, err := m.getZSet(op, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, z := range sortedRev(zset) {
+		if z.Member == member {
+			rank := int64(i)
+			return &rank, &z.Score, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s: member not found: %w", op, redis.Nil)
+}
+
+// ZTrim оставляет в множестве keep элементов с наибольшим score
+func (m *MemoryCache) ZTrim(_ context.Context, key string, keep int64) error {
+	const op = "memory.zTrim"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zset, _, err := m.getZSet(op, key)
+	if err != nil {
+		return err
+	}
+
+	members := sortedRev(zset)
+	for i := keep; i < int64(len(members)); i++ {
+		delete(zset, members[i].Member)
+	}
+	return nil
+}
+
+// Rename атомарно заменяет ключ newKey содержимым key
+func (m *MemoryCache) Rename(_ context.Context, key, newKey string) error {
+	const op = "memory.rename"
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, expiresAt, ok := m.entries.getWithExpiry(key)
+	if !ok {
+		return fmt.Errorf("%s: no such key", op)
+	}
+
+	m.entries.setUntil(newKey, value, expiresAt)
+	m.entries.delete(key)
+	return nil
+}
+
+// Close - nothing to release, exists to match RedisCache
+func (m *MemoryCache) Close() error {
+	return nil
+}
+
+func (m *MemoryCache) getBytes(op string, key string) ([]byte, error) {
+	value, ok := m.entries.get(key)
+	if !ok {
+		return nil, fmt.Errorf("%s: key not found: %w", op, redis.Nil)
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, errWrongType)
+	}
+	return data, nil
+}
+
+// getZSet - returns sorted set of the key with its expiration, empty set when key does not exist
+func (m *MemoryCache) getZSet(op string, key string) (memoryZSet, time.Time, error) {
+	value, expiresAt, ok := m.entries.getWithExpiry(key)
+	if !ok {
+		return memoryZSet{}, time.Time{}, nil
+	}
+
+	zset, ok := value.(memoryZSet)
+	if !ok {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", op, errWrongType)
+	}
+	return zset, expiresAt, nil
+}
+
+// addInt - adds delta to the counter keeping its expiration, expiration is set only for a new counter
+func (m *MemoryCache) addInt(op string, key string, delta int64, expiration time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, expiresAt, ok := m.entries.getWithExpiry(key)
+	if !ok {
+		m.entries.set(key, []byte(strconv.FormatInt(delta, 10)), expiration)
+		return delta, nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", op, errWrongType)
+	}
+	counter, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	counter += delta
+	m.entries.setUntil(key, []byte(strconv.FormatInt(counter, 10)), expiresAt)
+	return counter, nil
+}
+
+// sortedRev - members by score descending, equal scores by member descending as in Redis
+func sortedRev(zset memoryZSet) []ZSetMember {
+	members := make([]ZSetMember, 0, len(zset))
+	for member, score := range zset {
+		members = append(members, ZSetMember{Member: member, Score: score})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score > members[j].Score
+		}
+		return strconv.FormatInt(members[i].Member, 10) > strconv.FormatInt(members[j].Member, 10)
+	})
+	return members
+}
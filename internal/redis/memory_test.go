@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMemoryCacheSetVersioned(t *testing.T) {
+	type write struct {
+		version    int64
+		value      string
+		wantStored bool
+	}
+
+	tests := []struct {
+		name        string
+		writes      []write
+		wantVersion int64
+		wantValue   string
+	}{
+		{
+			name:        "first write",
+			writes:      []write{{version: 1, value: "a", wantStored: true}},
+			wantVersion: 1,
+			wantValue:   "a",
+		},
+		{
+			name:        "newer version replaces",
+			writes:      []write{{version: 1, value: "a", wantStored: true}, {version: 2, value: "b", wantStored: true}},
+			wantVersion: 2,
+			wantValue:   "b",
+		},
+		{
+			name:        "older version is skipped",
+			writes:      []write{{version: 3, value: "c", wantStored: true}, {version: 2, value: "b"}},
+			wantVersion: 3,
+			wantValue:   "c",
+		},
+		{
+			name:        "same version is skipped",
+			writes:      []write{{version: 2, value: "b", wantStored: true}, {version: 2, value: "other"}},
+			wantVersion: 2,
+			wantValue:   "b",
+		},
+		{
+			name:        "version zero on empty key",
+			writes:      []write{{version: 0, value: "a", wantStored: true}, {version: 0, value: "b"}},
+			wantVersion: 0,
+			wantValue:   "a",
+		},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryCache(config.MemoryCacheConfig{Size: 10})
+			for i, w := range tt.writes {
+				stored, err := m.SetVersioned(ctx, "key", w.version, w.value, time.Hour)
+				if err != nil {
+					t.Fatalf("write %d: SetVersioned() error = %v", i, err)
+				}
+				if stored != w.wantStored {
+					t.Errorf("write %d: SetVersioned() stored = %v, want %v", i, stored, w.wantStored)
+				}
+			}
+
+			var value string
+			version, err := m.GetVersioned(ctx, "key", &value)
+			if err != nil {
+				t.Fatalf("GetVersioned() error = %v", err)
+			}
+			if version != tt.wantVersion || value != tt.wantValue {
+				t.Errorf("GetVersioned() = %d %q, want %d %q", version, value, tt.wantVersion, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheVersionedAfterDelete(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache(config.MemoryCacheConfig{Size: 10})
+
+	if _, err := m.SetVersioned(ctx, "key", 5, "a", time.Hour); err != nil {
+		t.Fatalf("SetVersioned() error = %v", err)
+	}
+	if err := m.Invalidate(ctx, "key"); err != nil {
+		t.Fatalf("Invalidate() error = %v", err)
+	}
+
+	var value string
+	if _, err := m.GetVersioned(ctx, "key", &value); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetVersioned() after delete error = %v, want redis.Nil", err)
+	}
+
+	// После удаления сохраняется любая версия, в том числе старее удаленной
+	stored, err := m.SetVersioned(ctx, "key", 1, "b", time.Hour)
+	if err != nil || !stored {
+		t.Errorf("SetVersioned() after delete = %v, %v, want true", stored, err)
+	}
+}
+
+func TestMemoryCacheVersionedSchemaMismatch(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCache(config.MemoryCacheConfig{Size: 10})
+
+	if _, err := m.SetVersioned(ctx, "key", 1, "a", time.Hour); err != nil {
+		t.Fatalf("SetVersioned() error = %v", err)
+	}
+
+	// Значение другой схемы считается промахом и удаляется
+	var value int
+	if _, err := m.GetVersioned(ctx, "key", &value); !errors.Is(err, redis.Nil) {
+		t.Errorf("GetVersioned() error = %v, want redis.Nil", err)
+	}
+	if exists, _ := m.Exists(ctx, "key"); *exists != 0 {
+		t.Errorf("key with mismatched schema is kept")
+	}
+}
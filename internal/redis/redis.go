@@ -2,7 +2,7 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -15,6 +15,7 @@ type RedisCache struct {
     client *redis.Client
     Lifetime time.Duration
 	logger *slog.Logger
+	codec *Codec
 }
 
 func NewRedisCache(cfg config.RedisConfig, log *slog.Logger) (*RedisCache, error) {
@@ -33,7 +34,7 @@ func NewRedisCache(cfg config.RedisConfig, log *slog.Logger) (*RedisCache, error
 		logger.Warn("Couldn't connect Redis")
         return nil, fmt.Errorf("%s:%w", op, err)
     }
-    return &RedisCache{client: client, logger: log, Lifetime: cfg.Lifetime}, nil
+    return &RedisCache{client: client, logger: log, Lifetime: cfg.Lifetime, codec: NewCodec(cfg.BinaryKeyClasses)}, nil
 }
 
 func (r *RedisCache) SetString(ctx context.Context, key string, value string, expiration time.Duration) error {
@@ -99,42 +100,70 @@ func (r *RedisCache) Set(ctx context.Context, key string, value interface{}, exp
     const op = "redis.set"
     logger := r.logger.With("op", op)
 
-    jsonData, err := json.Marshal(value)
+    data, err := r.codec.Encode(key, value)
     if err != nil {
-        logger.Error("couldn't json marshal value", "error", err)
+        logger.Error("couldn't encode value", "error", err)
         return fmt.Errorf("%s: %w", op, err)
     }
 
-    err = r.client.Set(ctx, key, jsonData, expiration).Err()
+    return r.setBytes(ctx, key, data, expiration)
+}
+
+// Get получает объект из Redis и десериализует его в указанный тип
+func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
+    cachedData, err := r.getBytes(ctx, key)
+    if err != nil {
+        return err
+    }
+
+    return r.decode(ctx, key, cachedData, dest)
+}
+
+// decode декодирует значение, значение устаревшей схемы удаляется и считается отсутствующим
+func (r *RedisCache) decode(ctx context.Context, key string, data []byte, dest interface{}) error {
+    const op = "redis.decode"
+    logger := r.logger.With("op", op)
+
+    err := r.codec.Decode(data, dest)
+    if errors.Is(err, ErrSchemaMismatch) {
+        logger.Info("discarding cached value with stale schema", "key", key)
+        if err := r.client.Del(ctx, key).Err(); err != nil {
+            logger.Error("couldn't delete stale value", "error", err)
+        }
+        return fmt.Errorf("%s: %w: %w", op, err, redis.Nil)
+    }
     if err != nil {
+        logger.Error("couldn't decode value", "error", err)
+        return fmt.Errorf("%s: %w", op, err)
+    }
+    return nil
+}
+
+func (r *RedisCache) setBytes(ctx context.Context, key string, data []byte, expiration time.Duration) error {
+    const op = "redis.set"
+    logger := r.logger.With("op", op)
+
+    if err := r.client.Set(ctx, key, data, expiration).Err(); err != nil {
         logger.Error("couldn't set value", "error", err)
         return fmt.Errorf("%s: %w", op, err)
     }
     return nil
 }
 
-// Get получает объект из Redis и десериализует его в указанный тип
-func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
+func (r *RedisCache) getBytes(ctx context.Context, key string) ([]byte, error) {
     const op = "redis.get"
     logger := r.logger.With("op", op)
 
-    cachedData, err := r.client.Get(ctx, key).Result()
+    cachedData, err := r.client.Get(ctx, key).Bytes()
     if err != nil {
         if err == redis.Nil {
             logger.Debug("key not found", "key", key)
-            return fmt.Errorf("%s: key not found: %w", op, err)
+            return nil, fmt.Errorf("%s: key not found: %w", op, err)
         }
         logger.Error("couldn't get value", "error", err)
-        return fmt.Errorf("%s: %w", op, err)
-    }
-
-    err = json.Unmarshal([]byte(cachedData), dest)
-    if err != nil {
-        logger.Error("couldn't json unmarshal value", "error", err)
-        return fmt.Errorf("%s: %w", op, err)
+        return nil, fmt.Errorf("%s: %w", op, err)
     }
-
-    return nil
+    return cachedData, nil
 }
 
 
@@ -171,4 +200,32 @@ func (r *RedisCache) Close() error {
 	}
 	logger.Info("Redis client closed successfully")
     return nil
-}
\ No newline at end of file
+}
+
+// Incr увеличивает счетчик и задает время жизни при его создании
+func (r *RedisCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+    const op = "redis.incr"
+    logger := r.logger.With("op", op)
+
+    pipe := r.client.TxPipeline()
+    incr := pipe.Incr(ctx, key)
+    pipe.ExpireNX(ctx, key, expiration)
+
+    if _, err := pipe.Exec(ctx); err != nil {
+        logger.Error("couldn't increment value", "error", err)
+        return 0, fmt.Errorf("%s: %w", op, err)
+    }
+    return incr.Val(), nil
+}
+
+// Decr уменьшает счетчик
+func (r *RedisCache) Decr(ctx context.Context, key string) error {
+    const op = "redis.decr"
+    logger := r.logger.With("op", op)
+
+    if err := r.client.Decr(ctx, key).Err(); err != nil {
+        logger.Error("couldn't decrement value", "error", err)
+        return fmt.Errorf("%s: %w", op, err)
+    }
+    return nil
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setIfNewerScript сохраняет значение, только если его версия больше сохраненной
+var setIfNewerScript = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "v")
+if current and tonumber(current) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("HSET", KEYS[1], "v", ARGV[1], "d", ARGV[2])
+if tonumber(ARGV[3]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+// GetVersioned получает объект, сохраненный через SetVersioned, и возвращает его версию
+func (r *RedisCache) GetVersioned(ctx context.Context, key string, dest interface{}) (int64, error) {
+	const op = "redis.getVersioned"
+	logger := r.logger.With("op", op)
+
+	values, err := r.client.HMGet(ctx, key, "v", "d").Result()
+	if err != nil {
+		logger.Error("couldn't get value", "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawVersion, okVersion := values[0].(string)
+	data, okData := values[1].(string)
+	if !okVersion || !okData {
+		return 0, fmt.Errorf("%s: key not found: %w", op, redis.Nil)
+	}
+
+	version, err := strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := r.decode(ctx, key, []byte(data), dest); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// SetVersioned сохраняет объект, если в кэше нет версии новее или равной, возвращает true если значение сохранено
+func (r *RedisCache) SetVersioned(ctx context.Context, key string, version int64, value interface{}, expiration time.Duration) (bool, error) {
+	const op = "redis.setVersioned"
+	logger := r.logger.With("op", op)
+
+	data, err := r.codec.Encode(key, value)
+	if err != nil {
+		logger.Error("couldn't encode value", "error", err)
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stored, err := setIfNewerScript.Run(ctx, r.client, []string{key}, version, data, expiration.Milliseconds()).Int()
+	if err != nil {
+		logger.Error("couldn't set value", "error", err)
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return stored == 1, nil
+}
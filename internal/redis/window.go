@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WindowAdd добавляет событие в скользящее окно и возвращает число событий в окне вместе с ним
+func (r *RedisCache) WindowAdd(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	const op = "redis.windowAdd"
+	logger := r.logger.With("op", op)
+
+	pipe := r.client.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: now.UnixNano()})
+	count := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("couldn't add event to window", "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count.Val(), nil
+}
+
+// WindowCount возвращает число событий в скользящем окне
+func (r *RedisCache) WindowCount(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error) {
+	const op = "redis.windowCount"
+	logger := r.logger.With("op", op)
+
+	count, err := r.client.ZCount(ctx, key,
+		strconv.FormatInt(now.Add(-window).UnixNano(), 10),
+		strconv.FormatInt(now.UnixNano(), 10),
+	).Result()
+	if err != nil {
+		logger.Error("couldn't count window events", "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+// SwapInt сохраняет новое значение и возвращает предыдущее, nil если его не было
+func (r *RedisCache) SwapInt(ctx context.Context, key string, value int, expiration time.Duration) (*int, error) {
+	const op = "redis.swapInt"
+	logger := r.logger.With("op", op)
+
+	val, err := r.client.SetArgs(ctx, key, value, redis.SetArgs{Get: true, TTL: expiration}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		logger.Error("couldn't swap value", "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	prev, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &prev, nil
+}
@@ -0,0 +1,51 @@
+package characterservice
+
+import (
+	"context"
+	"time"
+
+	cache "github.com/Silverman143/character-service/internal/redis"
+)
+
+// Cache - cache backend used by the service. Missing keys are reported with errors wrapping redis.Nil.
+// Implemented by cache.LayeredCache over Redis and by cache.MemoryCache.
+type Cache interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// GetVersioned and SetVersioned - compare-and-set on version, older version never overwrites newer one
+	GetVersioned(ctx context.Context, key string, dest interface{}) (int64, error)
+	SetVersioned(ctx context.Context, key string, version int64, value interface{}, expiration time.Duration) (bool, error)
+	GetInt(ctx context.Context, key string) (*int, error)
+	SetInt(ctx context.Context, key string, value int, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (*int64, error)
+
+	// GetMany - decodes found keys into their destinations, returns found keys
+	GetMany(ctx context.Context, dests map[string]interface{}) ([]string, error)
+	SetMany(ctx context.Context, values map[string]interface{}, expiration time.Duration) error
+	DeleteMany(ctx context.Context, keys ...string) error
+	// Invalidate - deletes keys and notifies other instances holding local copies
+	Invalidate(ctx context.Context, keys ...string) error
+
+	Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
+	Decr(ctx context.Context, key string) error
+	SwapInt(ctx context.Context, key string, value int, expiration time.Duration) (*int, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) (string, error)
+	Unlock(ctx context.Context, key string, token string) error
+
+	WindowAdd(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error)
+	WindowCount(ctx context.Context, key string, now time.Time, window time.Duration) (int64, error)
+
+	ZAdd(ctx context.Context, key string, members ...cache.ZSetMember) error
+	ZAddGT(ctx context.Context, key string, members ...cache.ZSetMember) error
+	ZRevRange(ctx context.Context, key string, start, stop int64) ([]cache.ZSetMember, error)
+	ZRevRank(ctx context.Context, key string, member int64) (*int64, *float64, error)
+	ZTrim(ctx context.Context, key string, keep int64) error
+	Rename(ctx context.Context, key, newKey string) error
+}
+
+var (
+	_ Cache = (*cache.RedisCache)(nil)
+	_ Cache = (*cache.LayeredCache)(nil)
+	_ Cache = (*cache.MemoryCache)(nil)
+)
@@ -9,43 +9,68 @@ import (
 
 	referralgrpc "github.com/Silverman143/character-service/internal/clients/referral/grpc"
 	usergrpc "github.com/Silverman143/character-service/internal/clients/user/grpc"
+	"github.com/Silverman143/character-service/internal/config"
 	kafkaproducer "github.com/Silverman143/character-service/internal/kafka/producer"
 	"github.com/Silverman143/character-service/internal/lib/cachekeys"
-	cache "github.com/Silverman143/character-service/internal/redis"
 	"github.com/Silverman143/character-service/internal/services/character/dto"
 	"github.com/Silverman143/character-service/internal/storage"
-	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
 type Character struct {
 	log *slog.Logger
+	cfg *config.Config
 	//implementa stofage interfaces
 	appProvider storage.IAppProvider
 	characterProvider storage.ICharacterProvider
-    cache *cache.RedisCache
+	seasonProvider storage.ISeasonProvider
+	referralProvider storage.IReferralProvider
+	dailyRewardProvider storage.IDailyRewardProvider
+	questProvider storage.IQuestProvider
+	inventoryProvider storage.IInventoryProvider
+	promoCodeProvider storage.IPromoCodeProvider
+	pricingProvider storage.IPricingProvider
+	fraudProvider storage.IFraudProvider
+	// txProvider - runs multi-write flows in one transaction
+	txProvider storage.ITxProvider
+    cache Cache
     kafkaProducer *kafkaproducer.KafkaProducer
 	userClient *usergrpc.Client
 	referralClient *referralgrpc.Client
-
+	// loads - concurrent cache misses of the same key share one load
+	loads singleflight.Group
 }
 
 
-func New(	log * slog.Logger, 
-			appProvider storage.IAppProvider, 
-			characterProvider storage.ICharacterProvider,
-			cache *cache.RedisCache, 
-			kafkaProducer *kafkaproducer.KafkaProducer, 
-			userClient *usergrpc.Client,
-			referralClient *referralgrpc.Client) *Character{
+// Deps - external dependencies of the service, nil clients are allowed for jobs that don't call them
+type Deps struct {
+	Cache          Cache
+	KafkaProducer  *kafkaproducer.KafkaProducer
+	UserClient     *usergrpc.Client
+	ReferralClient *referralgrpc.Client
+}
+
+// New - creates service on top of repository, every storage interface of the service is served by it
+func New(log *slog.Logger, cfg *config.Config, repo storage.TxRepository, deps Deps) *Character {
 	return &Character{
 		log: 					log,
-		appProvider: 			appProvider,
-		characterProvider: 		characterProvider,	
-        cache:                  cache,
-        kafkaProducer:          kafkaProducer,
-		userClient: userClient,
-		referralClient:  		referralClient,
+		cfg: 					cfg,
+		appProvider: 			repo,
+		characterProvider: 		repo,
+		seasonProvider: 		repo,
+		referralProvider: 		repo,
+		dailyRewardProvider: 	repo,
+		questProvider: 			repo,
+		inventoryProvider: 		repo,
+		promoCodeProvider: 		repo,
+		pricingProvider: 		repo,
+		fraudProvider: 			repo,
+		txProvider: 			repo,
+		cache:                  deps.Cache,
+		kafkaProducer:          deps.KafkaProducer,
+		userClient: 			deps.UserClient,
+		referralClient:  		deps.ReferralClient,
 	}
 }
 
@@ -60,6 +85,9 @@ func (c *Character) CreateCharacter(ctx context.Context, userID int64) error {
 		return err
 	}
 
+	// Снимаем отметку отсутствующего персонажа и кэшируем созданного
+	c.invalidate(ctx, userID, cachekeys.MutationCreated)
+
 	logger.Info("character created successfully", "userID", userID)
 	return nil
 }
@@ -71,34 +99,20 @@ func (c *Character) GetCharacterLevel(ctx context.Context, userID int64)(*int, e
 
 	logger.Info("try to get character level", "userID", userID)
 
-	var level *int
-
-	// Check cached data
-    levelCacheKey := cachekeys.CharacterLevel(userID)
-    level, err := c.cache.GetInt(ctx, levelCacheKey)
-
-    if err == nil {
-        logger.Info("Level found in cache, returning cached level")
-        return level, nil
-    }
-    if err != redis.Nil {
-        logger.Error("Error accessing Redis cache", "error", err)
-    }
-
-	level, err = c.characterProvider.GetCharacterLevel(ctx, userID)
+	aggregate, err := c.characterAggregate(ctx, userID)
 	if err != nil{
-		logger.Error("Error with getting character level", "userID", userID, "error", err)
-		return level, fmt.Errorf("%s:%w", op, err)
-	}
-
-	err = c.cache.SetInt(ctx, levelCacheKey, *level, c.cache.Lifetime)
-	if err != nil {
-		logger.Error("failed to cache user character level", "error", err)
+		if errors.Is(err, storage.ErrCharacterNotFound) {
+			logger.Info("character not found", "userID", userID)
+		} else {
+			logger.Error("Error with getting character level", "userID", userID, "error", err)
+		}
+		return nil, fmt.Errorf("%s:%w", op, err)
 	}
+	level := aggregate.Character.CurrentLevel
 
 	logger.Info("character level getted successfully", "userID", userID)
 
-	return level, nil
+	return &level, nil
 }
 
 // GetCharacter - returns current user character data
@@ -108,75 +122,43 @@ func (c *Character) GetCharacter(ctx context.Context, userID int64)(*dto.GetChar
 
 	logger.Info("try to get character", "userID", userID)
 
-	var characterDto *dto.GetCharacterDTO
-
-	// Get from cache
-    characterCacheKey := cachekeys.CharacterData(userID)
-	err := c.cache.Get(ctx, characterCacheKey, characterDto)
-
-	if err == nil{
-		return characterDto, nil
-	}
-
-	if !errors.Is(err, redis.Nil){
-		logger.Error("error with getting cached character", "error", err)
-	}
-	// Get from db
-	characterDto, err = c.characterProvider.GetCharacter(ctx, userID)
-
+	aggregate, err := c.characterAggregate(ctx, userID)
 	if err != nil {
-		logger.Error("Error with getting character", "userID", userID, "error", err)
+		if errors.Is(err, storage.ErrCharacterNotFound) {
+			logger.Info("character not found", "userID", userID)
+		} else {
+			logger.Error("Error with getting character", "userID", userID, "error", err)
+		}
 		return &dto.GetCharacterDTO{}, fmt.Errorf("%s:%w", op, err)
 	}
-	// Save to cache
-	err = c.cache.Set(ctx, characterCacheKey, characterDto, c.cache.Lifetime)
 
-	if err != nil{
-		logger.Error("error with saving character in cache", "error", err)
-	}
+	c.touchActivity(ctx, userID)
 
-	return characterDto, nil
+	return c.deriveStats(ctx, aggregate), nil
 }
 
 // GetSkins - get all skins data
 func (c *Character) GetSkins(ctx context.Context, userID int64)(*dto.GetSkinsDTO, error){
 	const op = "service.character.GetSkins"
-	logger := c.log.With("op", op)
-
 
     var (
-        level    *int
+        aggregate *dto.CharacterAggregateDTO
         skinsDTO *dto.GetSkinsDTO
-        levelErr, skinsErr error
+        aggregateErr, skinsErr error
     )
 
-    // Параллельное получение уровня и скинов
+    // Параллельное получение персонажа и каталога скинов
     group, ctx := errgroup.WithContext(ctx)
 
     group.Go(func() error {
-        level, levelErr = c.GetCharacterLevel(ctx, userID)
-        return levelErr
+        // Уровень и купленные скины берутся из одного снимка персонажа
+        aggregate, aggregateErr = c.characterAggregate(ctx, userID)
+        return aggregateErr
     })
 
     group.Go(func() error {
-        // Получение скинов из кэша
-        err := c.cache.Get(ctx, cachekeys.AllSkinsInfo, &skinsDTO)
-        if err == nil {
-            return nil
-        }
-        if err != redis.Nil {
-            logger.Error("Error getting skins from cache", "error", err)
-        }
-
-        // Если данных нет в кэше, получаем из базы данных
-        skinsDTO, skinsErr = c.characterProvider.GetAllSkins(ctx)
-        if skinsErr == nil {
-            // Кэшируем результат
-            cacheErr := c.cache.Set(ctx, cachekeys.AllSkinsInfo, skinsDTO, c.cache.Lifetime)
-            if cacheErr != nil {
-                logger.Error("Failed to cache skins", "error", cacheErr)
-            }
-        }
+        // Промахи кэша каталога от параллельных запросов загружаются из базы один раз
+        skinsDTO, skinsErr = loadCached(ctx, c, cachekeys.AllSkinsInfo, c.cfg.Redis.Lifetime, c.characterProvider.GetAllSkins)
         return skinsErr
     })
 
@@ -186,7 +168,10 @@ func (c *Character) GetSkins(ctx context.Context, userID int64)(*dto.GetSkinsDTO
     }
 
     // Обновляем статус открытия скинов
-    skinsDTO.UpdateSkinsOpenStatus(*level)
+    skinsDTO.UpdateSkinsOpenStatus(aggregate.Character.CurrentLevel, aggregate.OwnedSkins)
+    skinsDTO.UpdateSkinsAvailability(time.Now())
+    skinsDTO.UpdateCollectionsStatus()
+    c.applySkinPrices(ctx, userID, skinsDTO)
 
 	return skinsDTO, nil
 }
@@ -196,36 +181,13 @@ func (c *Character) GetLevelsPrices(ctx context.Context) (*dto.LevelPriceListDTO
 	const op = "services.character.CacheLevelPrices"
 	logger := c.log.With("op", op)
 
-	// Создаем карту для хранения цен уровней
-	var pricesList dto.LevelPriceListDTO
-
-	// Пытаемся получить данные из кэша
-	err := c.cache.Get(ctx, cachekeys.LevelPrices, &pricesList)
-	if err == nil {
-		// Если данные найдены в кэше, возвращаем их
-		return &pricesList, nil
-	}
-
-	// Если ошибка - это отсутствие данных в кэше
-	if err == redis.Nil {
-		logger.Info("levels prices not exist in cache")
-	} else {
-		// Логируем любую другую ошибку кэширования
-		logger.Error("%s: error getting levels prices from cache", "error", err)
-	}
-
-	// Получаем цены уровней из characterProvider (Postgres)
-	levels, err := c.characterProvider.GetAllLevelPrices(ctx)
+	// Цены уровней кэшируются на 24 часа
+	levels, err := loadCached(ctx, c, cachekeys.LevelPrices, 24*time.Hour, c.characterProvider.GetAllLevelPrices)
 	if err != nil {
-		logger.Error("%s: error getting levels prices from postgres", "error", err)
+		logger.Error("error getting levels prices", "error", err)
 		return nil, fmt.Errorf("%s: failed to get level prices: %w", op, err)
 	}
 
-	// Кэшируем данные на 24 часа
-	if err := c.cache.Set(ctx, cachekeys.LevelPrices, levels, 24*time.Hour); err != nil {
-		logger.Error("%s: error caching levels prices", "error", err)
-	}
-
 	// Возвращаем карту цен уровней
 	return levels, nil
 }
@@ -249,6 +211,9 @@ func (c *Character) ChangeActiveSkin(ctx context.Context, userID int64, skinID i
         return fmt.Errorf("%s: %w", op, err)
     }
 
+    c.invalidate(ctx, userID, cachekeys.MutationActiveSkin)
+    c.trackQuestProgress(ctx, userID, dto.QuestActionSkinChanged, 1)
+
     logger.Info("Active skin changed successfully", "userID", userID, "skinID", skinID)
     return nil
 }
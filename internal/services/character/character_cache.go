@@ -0,0 +1,233 @@
+package characterservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// loadLockPoll - how often instance waiting for the load lock checks whether the key is cached
+const loadLockPoll = 50 * time.Millisecond
+
+// invalidate - drops every cached key depending on the mutations of the user data and notifies other instances.
+// Character aggregate is written through instead, it is deleted only when it could not be reloaded.
+// Must be called after each successful provider write, errors are only logged.
+func (c *Character) invalidate(ctx context.Context, userID int64, mutations ...cachekeys.Mutation) {
+	var (
+		keys []string
+		characterChanged bool
+	)
+	for _, mutation := range mutations {
+		if cachekeys.IsCharacterMutation(mutation) {
+			characterChanged = true
+		}
+		for _, key := range cachekeys.DependentKeys(mutation, userID) {
+			// Агрегат перезаписывается целиком, а не удаляется
+			if key != cachekeys.Character(userID) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if characterChanged {
+		if err := c.refreshAggregate(ctx, userID); err != nil {
+			c.log.Error("failed to refresh cached character", "userID", userID, "error", err)
+			keys = append(keys, cachekeys.Character(userID))
+		}
+	}
+
+	if err := c.cache.Invalidate(ctx, keys...); err != nil {
+		c.log.Error("failed to invalidate cache", "userID", userID, "mutations", mutations, "error", err)
+	}
+}
+
+// characterAggregate - returns cached character snapshot, concurrent misses share one load
+func (c *Character) characterAggregate(ctx context.Context, userID int64) (*dto.CharacterAggregateDTO, error) {
+	key := cachekeys.Character(userID)
+
+	var aggregate dto.CharacterAggregateDTO
+	_, err := c.cache.GetVersioned(ctx, key, &aggregate)
+	if err == nil {
+		return &aggregate, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		c.log.Error("error with getting cached character", "userID", userID, "error", err)
+	}
+
+	data, err, _ := c.loads.Do(key, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+
+		loaded, err := c.loadAggregate(loadCtx, userID)
+		if err != nil {
+			return nil, err
+		}
+		c.storeAggregate(loadCtx, loaded)
+
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data.([]byte), &aggregate); err != nil {
+		return nil, err
+	}
+	return &aggregate, nil
+}
+
+// loadAggregate - loads character from the database, users without character are remembered for a while.
+// With auto creation enabled missing character is created instead.
+func (c *Character) loadAggregate(ctx context.Context, userID int64) (*dto.CharacterAggregateDTO, error) {
+	missingKey := cachekeys.MissingCharacter(userID)
+
+	missing, err := c.cache.Exists(ctx, missingKey)
+	if err != nil {
+		c.log.Error("error with checking missing character marker", "userID", userID, "error", err)
+	} else if *missing > 0 {
+		return nil, storage.ErrCharacterNotFound
+	}
+
+	aggregate, err := c.characterProvider.GetCharacterAggregate(ctx, userID)
+	if !errors.Is(err, storage.ErrCharacterNotFound) {
+		return aggregate, err
+	}
+
+	// Реплика могла еще не получить только что созданного персонажа, отсутствие подтверждаем на основной базе
+	aggregate, err = c.characterProvider.GetCharacterAggregate(storage.WithPrimary(ctx), userID)
+	if !errors.Is(err, storage.ErrCharacterNotFound) {
+		return aggregate, err
+	}
+
+	if c.cfg.Characters.AutoCreate {
+		c.log.Info("creating character on first read", "userID", userID)
+		if err := c.characterProvider.CreateCharacter(ctx, userID); err != nil {
+			return nil, err
+		}
+		return c.characterProvider.GetCharacterAggregate(storage.WithPrimary(ctx), userID)
+	}
+
+	if err := c.cache.SetInt(ctx, missingKey, 1, c.cfg.Characters.MissingTTL); err != nil {
+		c.log.Error("error with saving missing character marker", "userID", userID, "error", err)
+	}
+	return nil, err
+}
+
+// refreshAggregate - writes character snapshot through to cache after the character is changed
+func (c *Character) refreshAggregate(ctx context.Context, userID int64) error {
+	// Снимок после своей записи читаем с основной базы, реплика может отставать
+	aggregate, err := c.characterProvider.GetCharacterAggregate(storage.WithPrimary(ctx), userID)
+	if err != nil {
+		return err
+	}
+	c.storeAggregate(ctx, aggregate)
+	return nil
+}
+
+// storeAggregate - saves snapshot unless another instance already cached newer version, errors are only logged
+func (c *Character) storeAggregate(ctx context.Context, aggregate *dto.CharacterAggregateDTO) {
+	userID := aggregate.UserID
+	stored, err := c.cache.SetVersioned(ctx, cachekeys.Character(userID), aggregate.Version, aggregate, c.jitter(c.cfg.Redis.Lifetime))
+	if err != nil {
+		c.log.Error("error with saving character in cache", "userID", userID, "error", err)
+		return
+	}
+	if !stored {
+		c.log.Debug("cached character is newer, snapshot skipped", "userID", userID, "version", aggregate.Version)
+	}
+}
+
+// loadCached - returns cached value of the key, on miss loads it and caches with jittered TTL.
+// Concurrent misses of the same key share one load, with configured load lock instances share it too.
+// Every caller gets its own copy, so the result may be modified.
+func loadCached[T any](ctx context.Context, c *Character, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+
+	err := c.cache.Get(ctx, key, &value)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		c.log.Error("error with getting cached value", "key", key, "error", err)
+	}
+
+	data, err, _ := c.loads.Do(key, func() (interface{}, error) {
+		// Загрузка не должна прерываться из-за отмены запроса первого из ожидающих
+		loadCtx := context.WithoutCancel(ctx)
+
+		if c.cfg.Redis.LoadLock > 0 {
+			var cached T
+			token, found := c.waitLoadLock(loadCtx, key, &cached)
+			if token != "" {
+				defer func() {
+					if err := c.cache.Unlock(loadCtx, cachekeys.LoadLock(key), token); err != nil {
+						c.log.Error("error with releasing load lock", "key", key, "error", err)
+					}
+				}()
+			}
+			if found {
+				return json.Marshal(cached)
+			}
+		}
+
+		loaded, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.cache.Set(loadCtx, key, loaded, c.jitter(ttl)); err != nil {
+			c.log.Error("error with saving value in cache", "key", key, "error", err)
+		}
+
+		return json.Marshal(loaded)
+	})
+	if err != nil {
+		return value, err
+	}
+
+	err = json.Unmarshal(data.([]byte), &value)
+	return value, err
+}
+
+// waitLoadLock - acquires load lock of the key or waits until another instance caches it into dest.
+// Returns lock token, empty when lock was not acquired in time, and whether the value is already cached.
+func (c *Character) waitLoadLock(ctx context.Context, key string, dest interface{}) (string, bool) {
+	lockTTL := c.cfg.Redis.LoadLock
+	deadline := time.Now().Add(lockTTL)
+
+	for {
+		token, err := c.cache.Lock(ctx, cachekeys.LoadLock(key), lockTTL)
+		if err != nil {
+			// Без блокировки загружаем сами, как и без Redis lock
+			return "", false
+		}
+
+		// Ключ мог закэшировать инстанс, только что отпустивший блокировку
+		if err := c.cache.Get(ctx, key, dest); err == nil {
+			return token, true
+		}
+		if token != "" {
+			return token, false
+		}
+
+		time.Sleep(loadLockPoll)
+		if time.Now().After(deadline) {
+			return "", false
+		}
+	}
+}
+
+// jitter - adds random part of the TTL, so keys cached together do not expire at the same moment
+func (c *Character) jitter(ttl time.Duration) time.Duration {
+	fraction := c.cfg.Redis.TTLJitter
+	if ttl <= 0 || fraction <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Float64()*fraction*float64(ttl))
+}
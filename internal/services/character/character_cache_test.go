@@ -0,0 +1,186 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+const testUserID = 7
+
+// aggregateStub - character provider serving aggregate of one user, counts database reads
+type aggregateStub struct {
+	storage.ICharacterProvider
+	aggregate *dto.CharacterAggregateDTO
+	err       error
+	loads     int
+}
+
+func (s *aggregateStub) GetCharacterAggregate(context.Context, int64) (*dto.CharacterAggregateDTO, error) {
+	s.loads++
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.aggregate == nil {
+		return nil, storage.ErrCharacterNotFound
+	}
+	aggregate := *s.aggregate
+	return &aggregate, nil
+}
+
+// setLevel - changes character in the database as a write of another request would, version grows like by trigger
+func (s *aggregateStub) setLevel(level int) {
+	s.aggregate.Character.CurrentLevel = level
+	s.aggregate.Version++
+}
+
+func newAggregateTest(aggregate *dto.CharacterAggregateDTO) (*Character, *aggregateStub, *cache.MemoryCache) {
+	provider := &aggregateStub{aggregate: aggregate}
+	memory := cache.NewMemoryCache(config.MemoryCacheConfig{Size: 100})
+	c := &Character{
+		log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cfg: &config.Config{
+			Redis:      config.RedisConfig{Lifetime: time.Hour},
+			Characters: config.CharactersConfig{MissingTTL: time.Hour},
+		},
+		characterProvider: provider,
+		cache:             memory,
+	}
+	return c, provider, memory
+}
+
+func testAggregate(level int, version int64) *dto.CharacterAggregateDTO {
+	return &dto.CharacterAggregateDTO{
+		UserID:    testUserID,
+		Version:   version,
+		Character: dto.GetCharacterDTO{CurrentLevel: level},
+	}
+}
+
+func TestCharacterAggregateInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// change - runs after the first read, before the checked one
+		change    func(c *Character, provider *aggregateStub, memory *cache.MemoryCache)
+		wantLevel int
+		wantLoads int
+	}{
+		{
+			name:      "second read is served from cache",
+			change:    func(*Character, *aggregateStub, *cache.MemoryCache) {},
+			wantLevel: 1,
+			wantLoads: 1,
+		},
+		{
+			name: "character mutation writes snapshot through",
+			change: func(c *Character, provider *aggregateStub, _ *cache.MemoryCache) {
+				provider.setLevel(2)
+				c.invalidate(ctx, testUserID, cachekeys.MutationLevel)
+			},
+			wantLevel: 2,
+			wantLoads: 2,
+		},
+		{
+			name: "several character mutations reload once",
+			change: func(c *Character, provider *aggregateStub, _ *cache.MemoryCache) {
+				provider.setLevel(3)
+				c.invalidate(ctx, testUserID, cachekeys.MutationLevel, cachekeys.MutationOwnedSkins, cachekeys.MutationBoosts)
+			},
+			wantLevel: 3,
+			wantLoads: 2,
+		},
+		{
+			name: "other mutations keep snapshot",
+			change: func(c *Character, provider *aggregateStub, _ *cache.MemoryCache) {
+				provider.setLevel(2)
+				c.invalidate(ctx, testUserID, cachekeys.MutationQuests, cachekeys.MutationSkinsCatalog)
+			},
+			wantLevel: 1,
+			wantLoads: 1,
+		},
+		{
+			name: "older snapshot does not replace newer",
+			change: func(c *Character, provider *aggregateStub, memory *cache.MemoryCache) {
+				// Другой инстанс уже закэшировал версию новее, чем видит эта запись
+				if _, err := memory.SetVersioned(ctx, cachekeys.Character(testUserID), 10, testAggregate(5, 10), time.Hour); err != nil {
+					t.Fatalf("failed to seed newer snapshot: %v", err)
+				}
+				provider.setLevel(2)
+				c.invalidate(ctx, testUserID, cachekeys.MutationLevel)
+			},
+			wantLevel: 5,
+			wantLoads: 2,
+		},
+		{
+			name: "failed refresh drops snapshot",
+			change: func(c *Character, provider *aggregateStub, _ *cache.MemoryCache) {
+				provider.setLevel(4)
+				provider.err = errors.New("database is down")
+				c.invalidate(ctx, testUserID, cachekeys.MutationLevel)
+				provider.err = nil
+			},
+			wantLevel: 4,
+			wantLoads: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, provider, memory := newAggregateTest(testAggregate(1, 1))
+
+			if _, err := c.characterAggregate(ctx, testUserID); err != nil {
+				t.Fatalf("first read error = %v", err)
+			}
+			tt.change(c, provider, memory)
+
+			got, err := c.characterAggregate(ctx, testUserID)
+			if err != nil {
+				t.Fatalf("characterAggregate() error = %v", err)
+			}
+			if got.Character.CurrentLevel != tt.wantLevel {
+				t.Errorf("characterAggregate() level = %d, want %d", got.Character.CurrentLevel, tt.wantLevel)
+			}
+			if provider.loads != tt.wantLoads {
+				t.Errorf("database reads = %d, want %d", provider.loads, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestCharacterAggregateMissingCharacter(t *testing.T) {
+	ctx := context.Background()
+	c, provider, _ := newAggregateTest(nil)
+
+	// Отсутствие проверяется на реплике и на основной базе, затем запоминается
+	for i := 0; i < 2; i++ {
+		if _, err := c.characterAggregate(ctx, testUserID); !errors.Is(err, storage.ErrCharacterNotFound) {
+			t.Fatalf("read %d: error = %v, want %v", i, err, storage.ErrCharacterNotFound)
+		}
+	}
+	if provider.loads != 2 {
+		t.Errorf("database reads = %d, want 2", provider.loads)
+	}
+
+	// Созданный персонаж снимает отметку и сразу попадает в кэш
+	provider.aggregate = testAggregate(1, 0)
+	c.invalidate(ctx, testUserID, cachekeys.MutationCreated)
+
+	got, err := c.characterAggregate(ctx, testUserID)
+	if err != nil {
+		t.Fatalf("characterAggregate() after creation error = %v", err)
+	}
+	if got.Character.CurrentLevel != 1 || provider.loads != 3 {
+		t.Errorf("characterAggregate() = level %d after %d reads, want level 1 after 3", got.Character.CurrentLevel, provider.loads)
+	}
+}
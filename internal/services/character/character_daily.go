@@ -0,0 +1,94 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+const defaultTimezone = "UTC"
+
+// ClaimDailyReward - claims reward of the current day in the user timezone and continues the streak.
+// Timezone from request is saved as user preference, otherwise stored preference or UTC is used.
+func (c *Character) ClaimDailyReward(ctx context.Context, userID int64, timezone string) (*dto.DailyRewardDTO, error) {
+	const op = "services.character.ClaimDailyReward"
+	logger := c.log.With("op", op)
+
+	calendar := c.cfg.DailyRewards.Calendar
+	if len(calendar) == 0 {
+		return nil, ErrDailyRewardsDisabled
+	}
+
+	timezone, err := c.resolveTimezone(ctx, userID, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Отметка дня и награда сохраняются в одной транзакции, монеты начисляются после нее
+	var (
+		claim  *dto.DailyClaimDTO
+		reward dto.RewardDTO
+	)
+	effects, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		var err error
+		claim, err = tx.CreateDailyClaim(ctx, userID, timezone)
+		if err != nil {
+			return err
+		}
+		if claim == nil {
+			return ErrDailyRewardAlreadyClaimed
+		}
+
+		reward = dto.NewRewardDTO(calendar[(claim.Streak-1)%len(calendar)])
+		return c.storeReward(ctx, tx, userID, reward, rewardSourceDaily, effects)
+	})
+	if err != nil {
+		if errors.Is(err, ErrDailyRewardAlreadyClaimed) {
+			return nil, err
+		}
+		logger.Error("Error with claiming daily reward", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.payCoins(ctx, userID, effects); err != nil {
+		logger.Error("Error with granting daily reward", "userID", userID, "streak", claim.Streak, "error", err)
+		if !effects.stored {
+			if delErr := c.dailyRewardProvider.DeleteDailyClaim(ctx, userID, claim.ClaimDate); delErr != nil {
+				logger.Error("Error with removing daily claim", "userID", userID, "error", delErr)
+			}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.trackQuestProgress(ctx, userID, dto.QuestActionDailyClaimed, 1)
+
+	logger.Info("daily reward claimed", "userID", userID, "streak", claim.Streak)
+	return &dto.DailyRewardDTO{DailyClaimDTO: *claim, Reward: reward}, nil
+}
+
+// resolveTimezone - validates timezone from request and stores it, falls back to stored one
+func (c *Character) resolveTimezone(ctx context.Context, userID int64, timezone string) (string, error) {
+	if timezone != "" {
+		// "Local" понятен только Go, Postgres такого пояса не знает
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+			return "", ErrInvalidTimezone
+		}
+		if err := c.dailyRewardProvider.SetTimezone(ctx, userID, timezone); err != nil {
+			return "", err
+		}
+		return timezone, nil
+	}
+
+	stored, err := c.dailyRewardProvider.GetTimezone(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if stored == "" {
+		return defaultTimezone, nil
+	}
+	return stored, nil
+}
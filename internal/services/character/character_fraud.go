@@ -0,0 +1,139 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+)
+
+const maxFlaggedLimit = 100
+
+// assessFraud - scores the operation by sliding window signals, blocks it or flags the character for review
+// depending on the score. Unavailable signals are skipped, the check must not break purchases when Redis is down.
+func (c *Character) assessFraud(ctx context.Context, userID int64, operation string) (*dto.FraudAssessmentDTO, error) {
+	const op = "services.character.assessFraud"
+	logger := c.log.With("op", op)
+
+	cfg := c.cfg.Fraud
+	now := time.Now()
+	assessment := &dto.FraudAssessmentDTO{Operation: operation, Decision: dto.FraudDecisionAllow}
+
+	addSignal := func(signal string, score int) {
+		assessment.Signals = append(assessment.Signals, signal)
+		assessment.Score += score
+	}
+
+	// Частота операций: в окне только завершенные операции, текущая будет следующей
+	operations, err := c.cache.WindowCount(ctx, cachekeys.FraudOperations(userID, operation), now, cfg.Window)
+	if err != nil {
+		logger.Error("failed to count operations", "userID", userID, "error", err)
+	} else if cfg.MaxOperations > 0 && operations+1 > cfg.MaxOperations {
+		addSignal(dto.FraudSignalVelocity, cfg.VelocityScore)
+	}
+
+	// Скачок числа рефералов с прошлой проверки
+	if cfg.MaxReferralJump > 0 {
+		if jump, err := c.referralJump(ctx, userID); err != nil {
+			logger.Error("failed to check referrals jump", "userID", userID, "error", err)
+		} else if jump > cfg.MaxReferralJump {
+			addSignal(dto.FraudSignalReferralJump, cfg.ReferralJumpScore)
+		}
+	}
+
+	// Повторные откаты платежей
+	rollbacks, err := c.cache.WindowCount(ctx, cachekeys.FraudRollbacks(userID), now, cfg.Window)
+	if err != nil {
+		logger.Error("failed to count payment rollbacks", "userID", userID, "error", err)
+	} else if cfg.MaxRollbacks > 0 && rollbacks >= cfg.MaxRollbacks {
+		addSignal(dto.FraudSignalRollbacks, cfg.RollbacksScore)
+	}
+
+	switch {
+	case assessment.Score == 0:
+		return assessment, nil
+	case cfg.BlockScore > 0 && assessment.Score >= cfg.BlockScore:
+		assessment.Decision = dto.FraudDecisionBlock
+	case cfg.ReviewScore > 0 && assessment.Score >= cfg.ReviewScore:
+		assessment.Decision = dto.FraudDecisionReview
+	}
+
+	logger.Warn("fraud signals triggered", "userID", userID, "operation", operation,
+		"score", assessment.Score, "signals", assessment.Signals, "decision", assessment.Decision)
+
+	if assessment.Decision == dto.FraudDecisionAllow {
+		return assessment, nil
+	}
+
+	flag := dto.FraudFlagDTO{
+		UserID:    userID,
+		Score:     assessment.Score,
+		Reasons:   strings.Join(assessment.Signals, ","),
+		Operation: operation,
+	}
+	if err := c.fraudProvider.FlagCharacter(ctx, flag); err != nil {
+		logger.Error("failed to flag character", "userID", userID, "error", err)
+	}
+
+	if assessment.Decision == dto.FraudDecisionBlock {
+		return assessment, ErrOperationBlocked
+	}
+	return assessment, nil
+}
+
+// recordOperation - counts successfully completed operation for the velocity check.
+// Failed and blocked attempts are not counted, otherwise retries after errors would look like fraud.
+func (c *Character) recordOperation(ctx context.Context, userID int64, operation string) {
+	if _, err := c.cache.WindowAdd(ctx, cachekeys.FraudOperations(userID, operation), time.Now(), c.cfg.Fraud.Window); err != nil {
+		c.log.Error("failed to count operation", "userID", userID, "operation", operation, "error", err)
+	}
+}
+
+// referralJump - returns referrals gained since the previous fraud check within the fraud window
+func (c *Character) referralJump(ctx context.Context, userID int64) (int, error) {
+	referrals, err := c.getReferralsCount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Снимок живет одно окно антифрода: скачок считается относительно проверки в пределах окна,
+	// а неактивные пользователи не копят ключи в Redis
+	prev, err := c.cache.SwapInt(ctx, cachekeys.FraudReferrals(userID), referrals, c.cfg.Fraud.Window)
+	if err != nil || prev == nil {
+		return 0, err
+	}
+
+	return referrals - *prev, nil
+}
+
+// rollbackPayment - cancels payment and counts the rollback for fraud checks
+func (c *Character) rollbackPayment(ctx context.Context, userID int64, paymentID string) {
+	if err := c.userClient.FinalizePayment(ctx, paymentID, false); err != nil {
+		c.log.Error("Error rolling back payment", "userID", userID, "paymentID", paymentID, "error", err)
+	}
+
+	if _, err := c.cache.WindowAdd(ctx, cachekeys.FraudRollbacks(userID), time.Now(), c.cfg.Fraud.Window); err != nil {
+		c.log.Error("failed to count payment rollback", "userID", userID, "error", err)
+	}
+}
+
+// ListFlaggedCharacters - returns characters flagged by fraud checks, used by admins
+// Not exposed over gRPC yet: protos_chadnaldo v0.0.45 has no RPC for it.
+func (c *Character) ListFlaggedCharacters(ctx context.Context, offset, limit uint) ([]dto.FraudFlagDTO, error) {
+	const op = "services.character.ListFlaggedCharacters"
+
+	if limit == 0 || limit > maxFlaggedLimit {
+		limit = maxFlaggedLimit
+	}
+
+	flags, err := c.fraudProvider.GetFlaggedCharacters(ctx, offset, limit)
+	if err != nil {
+		c.log.Error("Error with getting flagged characters", "op", op, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return flags, nil
+}
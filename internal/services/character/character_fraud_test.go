@@ -0,0 +1,86 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// fraudFlagsStub - fraud provider counting flagged characters
+type fraudFlagsStub struct {
+	storage.IFraudProvider
+	flags int
+}
+
+func (s *fraudFlagsStub) FlagCharacter(context.Context, dto.FraudFlagDTO) error {
+	s.flags++
+	return nil
+}
+
+func TestAssessFraudVelocity(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// attempts - assessments of operations that failed afterwards, they are not recorded
+		attempts  int
+		completed int
+		want      string
+		wantErr   error
+		wantFlags int
+	}{
+		{name: "first operation", want: dto.FraudDecisionAllow},
+		{name: "failed attempts are not counted", attempts: 5, want: dto.FraudDecisionAllow},
+		{name: "below limit", completed: 1, want: dto.FraudDecisionAllow},
+		{name: "operation above limit", completed: 2, want: dto.FraudDecisionBlock, wantErr: ErrOperationBlocked, wantFlags: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := &fraudFlagsStub{}
+			c := &Character{
+				log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+				cfg: &config.Config{Fraud: config.FraudConfig{
+					Window:        time.Hour,
+					MaxOperations: 2,
+					VelocityScore: 100,
+					ReviewScore:   50,
+					BlockScore:    80,
+				}},
+				fraudProvider: flags,
+				cache:         cache.NewMemoryCache(config.MemoryCacheConfig{Size: 10}),
+			}
+
+			for i := 0; i < tt.attempts; i++ {
+				if _, err := c.assessFraud(ctx, testUserID, dto.FraudOperationSkinPurchase); err != nil {
+					t.Fatalf("attempt %d: assessFraud() error = %v", i, err)
+				}
+			}
+			for i := 0; i < tt.completed; i++ {
+				c.recordOperation(ctx, testUserID, dto.FraudOperationSkinPurchase)
+			}
+			// Операции другого типа считаются отдельно
+			c.recordOperation(ctx, testUserID, dto.FraudOperationLevelUp)
+			c.recordOperation(ctx, testUserID, dto.FraudOperationLevelUp)
+
+			assessment, err := c.assessFraud(ctx, testUserID, dto.FraudOperationSkinPurchase)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("assessFraud() error = %v, want %v", err, tt.wantErr)
+			}
+			if assessment.Decision != tt.want {
+				t.Errorf("assessFraud() decision = %q, want %q", assessment.Decision, tt.want)
+			}
+			if flags.flags != tt.wantFlags {
+				t.Errorf("characters flagged %d times, want %d", flags.flags, tt.wantFlags)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// ListInventory - returns items of the user with quantities and equip status
+func (c *Character) ListInventory(ctx context.Context, userID int64) ([]dto.InventoryItemDTO, error) {
+	const op = "services.character.ListInventory"
+
+	inventory, err := c.inventoryProvider.GetInventory(ctx, userID)
+	if err != nil {
+		c.log.Error("Error with getting inventory", "op", op, "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inventory, nil
+}
+
+// EquipItem - equips owned item, item previously equipped in the same slot is unequipped
+func (c *Character) EquipItem(ctx context.Context, userID int64, itemID int) error {
+	const op = "services.character.EquipItem"
+	logger := c.log.With("op", op)
+
+	item, err := c.getItem(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.inventoryProvider.EquipItem(ctx, userID, *item); err != nil {
+		if errors.Is(err, storage.ErrItemNotOwned) {
+			return ErrItemNotOwned
+		}
+		logger.Error("Error with equipping item", "userID", userID, "itemID", itemID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.invalidate(ctx, userID, cachekeys.MutationEquipment)
+
+	logger.Info("item equipped", "userID", userID, "itemID", itemID, "slot", item.Slot)
+	return nil
+}
+
+// UnequipItem - frees the slot occupied by the item
+func (c *Character) UnequipItem(ctx context.Context, userID int64, itemID int) error {
+	const op = "services.character.UnequipItem"
+	logger := c.log.With("op", op)
+
+	unequipped, err := c.inventoryProvider.UnequipItem(ctx, userID, itemID)
+	if err != nil {
+		logger.Error("Error with unequipping item", "userID", userID, "itemID", itemID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !unequipped {
+		return ErrItemNotEquipped
+	}
+
+	c.invalidate(ctx, userID, cachekeys.MutationEquipment)
+
+	logger.Info("item unequipped", "userID", userID, "itemID", itemID)
+	return nil
+}
+
+// GrantItem - adds items to the user inventory, used by admins. Returns new quantity of the item
+func (c *Character) GrantItem(ctx context.Context, userID int64, itemID int, quantity int) (int, error) {
+	const op = "services.character.GrantItem"
+	logger := c.log.With("op", op)
+
+	if quantity <= 0 {
+		return 0, ErrInvalidItemQuantity
+	}
+
+	if _, err := c.getItem(ctx, itemID); err != nil {
+		return 0, err
+	}
+
+	if _, err := c.characterProvider.GetCharacterLevel(storage.WithPrimary(ctx), userID); err != nil {
+		logger.Error("Error with getting character", "userID", userID, "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	total, err := c.inventoryProvider.GrantItem(ctx, userID, itemID, quantity)
+	if err != nil {
+		logger.Error("Error with granting item", "userID", userID, "itemID", itemID, "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.Info("item granted", "userID", userID, "itemID", itemID, "quantity", quantity)
+	return total, nil
+}
+
+func (c *Character) getItem(ctx context.Context, itemID int) (*dto.ItemDTO, error) {
+	item, err := c.inventoryProvider.GetItem(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			return nil, ErrItemIsNotExist
+		}
+		return nil, err
+	}
+	return item, nil
+}
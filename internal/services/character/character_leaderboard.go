@@ -0,0 +1,252 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	LeaderboardLevel  = "level"
+	LeaderboardMining = "mining"
+
+	maxLeaderboardLimit = 100
+	rebuildBatchSize    = 1000
+	// rebuildMarkerTTL - marker of the failed rebuild expires by itself, so writes stop going to the rebuilt set
+	rebuildMarkerTTL = time.Hour
+)
+
+var leaderboardKeys = map[string]string{
+	LeaderboardLevel:  cachekeys.LeaderboardLevel,
+	LeaderboardMining: cachekeys.LeaderboardMining,
+}
+
+// GetLeaderboard - returns page of the leaderboard ordered by score
+// Not exposed over gRPC yet: protos_chadnaldo v0.0.45 has no RPC for it.
+func (c *Character) GetLeaderboard(ctx context.Context, board string, offset, limit int64) ([]dto.LeaderboardEntryDTO, error) {
+	const op = "services.character.GetLeaderboard"
+	logger := c.log.With("op", op)
+
+	key, ok := leaderboardKeys[board]
+	if !ok {
+		return nil, ErrUnknownLeaderboard
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > maxLeaderboardLimit {
+		limit = maxLeaderboardLimit
+	}
+
+	members, err := c.cache.ZRevRange(ctx, key, offset, offset+limit-1)
+	if err != nil {
+		logger.Error("Error with getting leaderboard", "board", board, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries := make([]dto.LeaderboardEntryDTO, len(members))
+	for i, m := range members {
+		entries[i] = dto.LeaderboardEntryDTO{
+			UserID: m.Member,
+			Rank:   offset + int64(i) + 1,
+			Score:  int64(m.Score),
+		}
+	}
+
+	return entries, nil
+}
+
+// GetRank - returns user positions in all leaderboards
+// Not exposed over gRPC yet: protos_chadnaldo v0.0.45 has no RPC for it.
+func (c *Character) GetRank(ctx context.Context, userID int64) (*dto.UserRankDTO, error) {
+	const op = "services.character.GetRank"
+
+	level, err := c.getBoardEntry(ctx, cachekeys.LeaderboardLevel, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mining, err := c.getBoardEntry(ctx, cachekeys.LeaderboardMining, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.UserRankDTO{Level: level, Mining: mining}, nil
+}
+
+func (c *Character) getBoardEntry(ctx context.Context, key string, userID int64) (dto.LeaderboardEntryDTO, error) {
+	entry := dto.LeaderboardEntryDTO{UserID: userID}
+
+	rank, score, err := c.cache.ZRevRank(ctx, key, userID)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return entry, nil
+		}
+		return entry, err
+	}
+
+	entry.Rank = *rank + 1
+	entry.Score = int64(*score)
+	return entry, nil
+}
+
+// AddMinedCoins - saves claimed mining coins and updates mining leaderboard.
+// Claim is counted once, repeated delivery of the same claim is ignored.
+func (c *Character) AddMinedCoins(ctx context.Context, userID int64, claimID string, coins int64) error {
+	const op = "services.character.AddMinedCoins"
+	logger := c.log.With("op", op)
+
+	if coins <= 0 {
+		return nil
+	}
+	if claimID == "" {
+		return ErrMiningClaimIDRequired
+	}
+
+	total, err := c.characterProvider.AddMinedCoins(ctx, userID, claimID, coins)
+	if err != nil {
+		logger.Error("Error with adding mined coins", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if total == nil {
+		logger.Info("mining claim is already counted", "userID", userID, "claimID", claimID)
+		return nil
+	}
+
+	err = c.addLeaderboardScore(ctx, cachekeys.LeaderboardMining, cache.ZSetMember{Member: userID, Score: float64(*total)})
+	if err != nil {
+		logger.Error("failed to update mining leaderboard", "userID", userID, "error", err)
+	}
+
+	if perXP := c.cfg.Seasons.MiningCoinsPerXP; perXP > 0 {
+		if err := c.AddSeasonXP(ctx, userID, coins/perXP); err != nil {
+			logger.Error("failed to add season xp", "userID", userID, "error", err)
+		}
+	}
+
+	c.trackQuestProgress(ctx, userID, dto.QuestActionMiningClaimed, 1)
+
+	return nil
+}
+
+// updateLevelLeaderboard - writes current level score of the user into level leaderboard
+func (c *Character) updateLevelLeaderboard(ctx context.Context, userID int64) error {
+	// Уровень только что изменен, реплика может вернуть старый
+	ranking, err := c.characterProvider.GetCharacterRanking(storage.WithPrimary(ctx), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.addLeaderboardScore(ctx, cachekeys.LeaderboardLevel, cache.ZSetMember{Member: userID, Score: ranking.LevelScore()})
+}
+
+// addLeaderboardScore - raises score of the member in the leaderboard. While the leaderboard is rebuilt
+// the score is also written into the rebuilt set, otherwise it is lost when that set replaces the live one.
+func (c *Character) addLeaderboardScore(ctx context.Context, key string, member cache.ZSetMember) error {
+	rebuilding, err := c.cache.Exists(ctx, cachekeys.LeaderboardRebuilding(key))
+	if err != nil {
+		return err
+	}
+
+	// Сначала пишем в пересобираемое множество: если подмена случится между записями, вторая попадет уже в новое
+	if *rebuilding > 0 {
+		if err := c.cache.ZAddGT(ctx, cachekeys.LeaderboardRebuild(key), member); err != nil {
+			return err
+		}
+	}
+	return c.cache.ZAddGT(ctx, key, member)
+}
+
+// RebuildLeaderboards - reconstructs all leaderboards from characters table
+func (c *Character) RebuildLeaderboards(ctx context.Context) (int, error) {
+	const op = "services.character.RebuildLeaderboards"
+	logger := c.log.With("op", op)
+
+	levelTmp := cachekeys.LeaderboardRebuild(cachekeys.LeaderboardLevel)
+	miningTmp := cachekeys.LeaderboardRebuild(cachekeys.LeaderboardMining)
+
+	for _, key := range []string{levelTmp, miningTmp} {
+		if err := c.cache.Delete(ctx, key); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Пока идет пересборка, новые очки пишутся и во временные множества, см. addLeaderboardScore
+	markers := []string{
+		cachekeys.LeaderboardRebuilding(cachekeys.LeaderboardLevel),
+		cachekeys.LeaderboardRebuilding(cachekeys.LeaderboardMining),
+	}
+	for _, marker := range markers {
+		if err := c.cache.SetInt(ctx, marker, 1, rebuildMarkerTTL); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	defer func() {
+		if err := c.cache.DeleteMany(ctx, markers...); err != nil {
+			logger.Error("failed to remove rebuild markers", "error", err)
+		}
+	}()
+
+	var (
+		afterUserID int64
+		total       int
+	)
+
+	for {
+		rankings, err := c.characterProvider.GetCharactersRanking(ctx, afterUserID, rebuildBatchSize)
+		if err != nil {
+			logger.Error("Error with getting characters", "afterUserID", afterUserID, "error", err)
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		if len(rankings) == 0 {
+			break
+		}
+
+		levelMembers := make([]cache.ZSetMember, len(rankings))
+		miningMembers := make([]cache.ZSetMember, 0, len(rankings))
+		for i, r := range rankings {
+			levelMembers[i] = cache.ZSetMember{Member: r.UserID, Score: r.LevelScore()}
+			if r.MinedCoins > 0 {
+				miningMembers = append(miningMembers, cache.ZSetMember{Member: r.UserID, Score: float64(r.MinedCoins)})
+			}
+		}
+
+		// Очки, записанные во время пересборки, могут быть новее прочитанных из базы
+		if err := c.cache.ZAddGT(ctx, levelTmp, levelMembers...); err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := c.cache.ZAddGT(ctx, miningTmp, miningMembers...); err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+
+		total += len(rankings)
+		afterUserID = rankings[len(rankings)-1].UserID
+	}
+
+	// Подменяем старые множества новыми одной операцией на каждое
+	for tmp, key := range map[string]string{levelTmp: cachekeys.LeaderboardLevel, miningTmp: cachekeys.LeaderboardMining} {
+		exists, err := c.cache.Exists(ctx, tmp)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		if *exists == 0 {
+			if err := c.cache.Delete(ctx, key); err != nil {
+				return total, fmt.Errorf("%s: %w", op, err)
+			}
+			continue
+		}
+		if err := c.cache.Rename(ctx, tmp, key); err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	logger.Info("leaderboards rebuilt", "characters", total)
+	return total, nil
+}
@@ -2,66 +2,69 @@ package characterservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/Silverman143/character-service/internal/lib/cachekeys"
 	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
 	"github.com/google/uuid"
 )
 
-func (c *Character) LevelUpCharacter(ctx context.Context, userID int64) (newLevel *int, coinsBalance *int64, err error) {
+// LevelUpCharacter - upgrades level paying with the chosen method, PaymentMethodAuto keeps previous behaviour
+func (c *Character) LevelUpCharacter(ctx context.Context, userID int64, method string) (*dto.LevelUpResultDTO, error) {
 	const op = "service.character.LevelUpCharacter"
 	logger := c.log.With("op", op)
 
-	// Получаем текущий уровень персонажа
-	level, err := c.GetCharacterLevel(ctx, userID)
+	// Получаем цену следующего уровня с учетом скидочных кампаний
+	quote, err := c.GetLevelUpQuote(ctx, userID)
 	if err != nil {
-		logger.Error("failed to get character level")
-		return nil, nil, fmt.Errorf("%s: failed to get character level: %w", op, err)
+		logger.Error("failed to get level up quote", "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Получаем цены уровней
-	levelsPrices, err := c.GetLevelsPrices(ctx)
-	if err != nil {
-		logger.Error("failed to get levels prices")
-		return level, nil, fmt.Errorf("%s: failed to get levels prices: %w", op, err)
+	nextLevelPrice := dto.LevelPriceDTO{
+		Level:                quote.Level,
+		CoinsPrice:           quote.Price,
+		ReferralsPrice:       quote.ReferralsPrice,
+		ReferralsForFreeOpen: quote.ReferralsForFreeOpen,
 	}
 
-	// Проверяем, есть ли цена для следующего уровня
-	nextLevelPrice, exists := levelsPrices.GetLevelPrice(*level + 1)
-	if !exists {
-		logger.Error("no price for the next level")
-		return level, nil, fmt.Errorf("%s: no price for the next level", op)
+	// Проверяем операцию антифродом
+	if _, err := c.assessFraud(ctx, userID, dto.FraudOperationLevelUp); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	// Получаем количество монет и рефералов пользователя
 	coins, referrals, err := c.getUserInfo(ctx, userID)
 	if err != nil {
 		logger.Error("Error with getting user info", slog.Any("err", err))
-		return level, nil, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Проверяем возможность повышения уровня
-	canLevelUp, isFreeLevelUp := c.canLevelUp(nextLevelPrice, coins, referrals)
-	if !canLevelUp {
-		logger.Error("not enough coins or referrals to upgrade level")
-		return level, nil, fmt.Errorf("%s: not enough coins or referrals to upgrade level", op)
+	// Проверяем возможность повышения уровня выбранным способом
+	method, err = c.resolvePaymentMethod(method, nextLevelPrice, coins, referrals)
+	if err != nil {
+		logger.Error("can not upgrade level", "method", method, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	// Повышаем уровень
-	newLevel, coins, err = c.upgradeLevel(ctx, userID, isFreeLevelUp, coins, nextLevelPrice.CoinsPrice)
+	newLevel, coins, err := c.upgradeLevel(ctx, userID, method, coins, nextLevelPrice)
 	if err != nil {
 		logger.Error("Error wuth upgrade level", slog.Any("error", err))
-		return level, nil, fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	c.recordOperation(ctx, userID, dto.FraudOperationLevelUp)
 
-	// Обновляем кэш нового уровня
-	if err := c.cacheNewLevel(ctx, userID, *newLevel); err != nil {
-		logger.Error("failed to cache user character level", "error", err)
-	}
+	c.trackQuestProgress(ctx, userID, dto.QuestActionLevelUp, 1)
 
-	return newLevel, &coins, nil
+	return &dto.LevelUpResultDTO{
+		NewLevel:      *newLevel,
+		CoinsBalance:  coins,
+		PaymentMethod: method,
+	}, nil
 }
 
 func (c *Character) getUserInfo(ctx context.Context, userID int64) (coins int64, referrals int, err error) {
@@ -70,7 +73,7 @@ func (c *Character) getUserInfo(ctx context.Context, userID int64) (coins int64,
 		return 0, 0, fmt.Errorf("failed to get user coins balance: %w", err)
 	}
 
-	referrals, err = c.referralClient.GetReferralsAmount(ctx, userID)
+	referrals, err = c.getReferralsCount(ctx, userID)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get referrals amount: %w", err)
 	}
@@ -78,50 +81,93 @@ func (c *Character) getUserInfo(ctx context.Context, userID int64) (coins int64,
 	return coins, referrals, nil
 }
 
-func (c *Character) canLevelUp(price dto.LevelPriceDTO, coins int64, referrals int) (bool, bool) {
-	if price.CoinsPrice <= coins {
-		return true, false
-	}
-	if price.ReferralsForFreeOpen <= int64(referrals) {
-		return true, true
+// resolvePaymentMethod - checks that chosen method can pay for the level, auto method is resolved to coins or free.
+// Referral credits are checked against spent ones atomically in storage.
+func (c *Character) resolvePaymentMethod(method string, price dto.LevelPriceDTO, coins int64, referrals int) (string, error) {
+	switch method {
+	case dto.PaymentMethodAuto, "":
+		if price.CoinsPrice <= coins {
+			return dto.PaymentMethodCoins, nil
+		}
+		if price.ReferralsForFreeOpen <= int64(referrals) {
+			return dto.PaymentMethodFree, nil
+		}
+		return method, ErrNotEnoughCoins
+
+	case dto.PaymentMethodCoins:
+		if price.CoinsPrice > coins {
+			return method, ErrNotEnoughCoins
+		}
+
+	case dto.PaymentMethodReferrals:
+		if price.ReferralsPrice <= 0 {
+			return method, ErrPaymentMethodUnavailable
+		}
+		if price.ReferralsPrice > int64(referrals) {
+			return method, ErrNotEnoughReferrals
+		}
+
+	case dto.PaymentMethodFree:
+		if price.ReferralsForFreeOpen > int64(referrals) {
+			return method, ErrNotEnoughReferrals
+		}
+
+	default:
+		return method, ErrUnknownPaymentMethod
 	}
-	return false, false
-}
 
-func (c *Character) upgradeLevel(ctx context.Context, userID int64, isFreeLevelUp bool, coins int64, price int64) (*int, int64, error) {
-	var newLevel *int
-	var err error
+	return method, nil
+}
 
-	if !isFreeLevelUp {
-		paymentID := uuid.New().String()
-		if err := c.userClient.InitiatePayment(ctx, userID, price, paymentID); err != nil {
-			return newLevel, coins, fmt.Errorf("failed to initiate payment: %w", err)
+// upgradeLevel - upgrades level in one transaction, the change log row is written by trigger in it too.
+// Cache and level leaderboard are updated after commit.
+func (c *Character) upgradeLevel(ctx context.Context, userID int64, method string, coins int64, price dto.LevelPriceDTO) (*int, int64, error) {
+	var paymentID string
+	if method == dto.PaymentMethodCoins {
+		paymentID = uuid.New().String()
+		if err := c.userClient.InitiatePayment(ctx, userID, price.CoinsPrice, paymentID); err != nil {
+			return nil, coins, fmt.Errorf("failed to initiate payment: %w", err)
 		}
+	}
 
-		newLevel, err = c.characterProvider.UpgradeCharacterLevel(ctx, userID)
+	var newLevel *int
+	_, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		var err error
+		// Цена посчитана для перехода на price.Level, уровень повышается только с предыдущего
+		fromLevel := price.Level - 1
+		switch method {
+		case dto.PaymentMethodReferrals:
+			newLevel, err = tx.UpgradeCharacterLevelForReferrals(ctx, userID, fromLevel, price.ReferralsPrice)
+		default:
+			newLevel, err = tx.UpgradeCharacterLevel(ctx, userID, fromLevel)
+		}
 		if err != nil {
-			if rollbackErr := c.userClient.FinalizePayment(ctx, paymentID, false); rollbackErr != nil {
-				c.log.Error("Error rolling back payment", "userID", userID, "paymentID", paymentID, "error", rollbackErr)
-			}
-			return newLevel, coins, fmt.Errorf("failed to upgrade character level: %w", err)
+			return err
 		}
 
-		if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
-			return newLevel, coins, fmt.Errorf("failed to finalize payment: %w", err)
+		effects.mutations = append(effects.mutations, cachekeys.MutationLevel)
+		effects.levelUp = true
+		return nil
+	})
+	if err != nil {
+		if paymentID != "" {
+			c.rollbackPayment(ctx, userID, paymentID)
 		}
+		if errors.Is(err, storage.ErrNotEnoughReferrals) {
+			return nil, coins, ErrNotEnoughReferrals
+		}
+		if errors.Is(err, storage.ErrLevelChanged) {
+			return nil, coins, ErrLevelChanged
+		}
+		return nil, coins, fmt.Errorf("failed to upgrade character level: %w", err)
+	}
 
-		coins -= price
-	} else {
-		newLevel, err = c.characterProvider.UpgradeCharacterLevel(ctx, userID)
-		if err != nil {
-			return newLevel, coins, fmt.Errorf("failed to upgrade character level: %w", err)
+	if paymentID != "" {
+		if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+			return newLevel, coins, fmt.Errorf("failed to finalize payment: %w", err)
 		}
+		coins -= price.CoinsPrice
 	}
 
 	return newLevel, coins, nil
 }
-
-func (c *Character) cacheNewLevel(ctx context.Context, userID int64, newLevel int) error {
-	cachekey := cachekeys.CharacterLevel(userID)
-	return c.cache.SetInt(ctx, cachekey, newLevel, c.cache.Lifetime)
-}
\ No newline at end of file
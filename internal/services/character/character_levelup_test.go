@@ -0,0 +1,55 @@
+package characterservice
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+)
+
+func TestResolvePaymentMethod(t *testing.T) {
+	price := dto.LevelPriceDTO{
+		Level:                5,
+		CoinsPrice:           100,
+		ReferralsPrice:       3,
+		ReferralsForFreeOpen: 10,
+	}
+	noReferralsPrice := price
+	noReferralsPrice.ReferralsPrice = 0
+
+	tests := []struct {
+		name      string
+		method    string
+		price     dto.LevelPriceDTO
+		coins     int64
+		referrals int
+		want      string
+		wantErr   error
+	}{
+		{name: "auto pays with coins", method: dto.PaymentMethodAuto, price: price, coins: 100, want: dto.PaymentMethodCoins},
+		{name: "empty method is auto", method: "", price: price, coins: 150, want: dto.PaymentMethodCoins},
+		{name: "auto falls back to free open", method: dto.PaymentMethodAuto, price: price, coins: 99, referrals: 10, want: dto.PaymentMethodFree},
+		{name: "auto without coins and referrals", method: dto.PaymentMethodAuto, price: price, coins: 99, referrals: 9, wantErr: ErrNotEnoughCoins},
+		{name: "coins", method: dto.PaymentMethodCoins, price: price, coins: 100, want: dto.PaymentMethodCoins},
+		{name: "not enough coins", method: dto.PaymentMethodCoins, price: price, coins: 99, referrals: 100, wantErr: ErrNotEnoughCoins},
+		{name: "referrals", method: dto.PaymentMethodReferrals, price: price, referrals: 3, want: dto.PaymentMethodReferrals},
+		{name: "not enough referrals", method: dto.PaymentMethodReferrals, price: price, coins: 1000, referrals: 2, wantErr: ErrNotEnoughReferrals},
+		{name: "referrals price is not set", method: dto.PaymentMethodReferrals, price: noReferralsPrice, referrals: 100, wantErr: ErrPaymentMethodUnavailable},
+		{name: "free", method: dto.PaymentMethodFree, price: price, referrals: 10, want: dto.PaymentMethodFree},
+		{name: "free below threshold", method: dto.PaymentMethodFree, price: price, coins: 1000, referrals: 9, wantErr: ErrNotEnoughReferrals},
+		{name: "unknown method", method: "gems", price: price, coins: 1000, referrals: 100, wantErr: ErrUnknownPaymentMethod},
+	}
+
+	c := &Character{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.resolvePaymentMethod(tt.method, tt.price, tt.coins, tt.referrals)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolvePaymentMethod() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("resolvePaymentMethod() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+// RenameCharacter - sets character nickname. The first rename and renames after cooldown are free,
+// during cooldown rename is paid with coins when allowPaid is set and rename price is configured.
+func (c *Character) RenameCharacter(ctx context.Context, userID int64, nickname string, allowPaid bool) (*dto.RenameResultDTO, error) {
+	const op = "services.character.RenameCharacter"
+	logger := c.log.With("op", op)
+
+	nickname = strings.TrimSpace(nickname)
+	if err := c.validateNickname(nickname); err != nil {
+		return nil, err
+	}
+
+	cooldown := c.cfg.Nicknames.RenameCooldown
+
+	// Проверка текущего ника и бесплатное переименование выполняются в одной транзакции
+	var paid bool
+	_, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		current, err := tx.GetNickname(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if current.Nickname != nil && *current.Nickname == nickname {
+			return ErrNicknameUnchanged
+		}
+
+		paid = current.ChangedAt != nil && time.Now().Before(current.ChangedAt.Add(cooldown))
+		if paid {
+			return nil
+		}
+
+		updated, err := tx.SetNickname(ctx, userID, nickname, cooldown)
+		if err != nil {
+			return err
+		}
+		// Кулдаун по часам базы еще не прошел или параллельное переименование успело раньше
+		paid = !updated
+		if updated {
+			effects.mutations = append(effects.mutations, cachekeys.MutationNickname)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNicknameUnchanged) {
+			return nil, err
+		}
+		return nil, c.nicknameError(op, userID, err)
+	}
+
+	if paid {
+		if err := c.paidRename(ctx, userID, nickname, allowPaid); err != nil {
+			if errors.Is(err, ErrRenameCooldown) || errors.Is(err, ErrNicknameTaken) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	logger.Info("character renamed", "userID", userID, "paid", paid)
+	return &dto.RenameResultDTO{
+		Nickname:         nickname,
+		Paid:             paid,
+		NextFreeRenameAt: time.Now().Add(cooldown),
+	}, nil
+}
+
+// paidRename - renames character during cooldown for configured price
+func (c *Character) paidRename(ctx context.Context, userID int64, nickname string, allowPaid bool) error {
+	const op = "services.character.paidRename"
+
+	price := c.cfg.Nicknames.RenamePrice
+	if !allowPaid || price <= 0 {
+		return ErrRenameCooldown
+	}
+
+	paymentID := uuid.New().String()
+	if err := c.userClient.InitiatePayment(ctx, userID, price, paymentID); err != nil {
+		return fmt.Errorf("failed to initiate payment: %w", err)
+	}
+
+	_, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		if _, err := tx.SetNickname(ctx, userID, nickname, 0); err != nil {
+			return err
+		}
+		effects.mutations = append(effects.mutations, cachekeys.MutationNickname)
+		return nil
+	})
+	if err != nil {
+		c.rollbackPayment(ctx, userID, paymentID)
+		return c.nicknameError(op, userID, err)
+	}
+
+	if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+		return fmt.Errorf("failed to finalize payment: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Character) nicknameError(op string, userID int64, err error) error {
+	if errors.Is(err, storage.ErrNicknameTaken) {
+		return ErrNicknameTaken
+	}
+	c.log.Error("Error with saving nickname", "op", op, "userID", userID, "error", err)
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// validateNickname - checks nickname length, allowed characters and configured blocklist
+func (c *Character) validateNickname(nickname string) error {
+	cfg := c.cfg.Nicknames
+
+	length := utf8.RuneCountInString(nickname)
+	if length < cfg.MinLength || length > cfg.MaxLength {
+		return ErrInvalidNickname
+	}
+
+	for _, r := range nickname {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return ErrInvalidNickname
+		}
+	}
+
+	normalized := normalizeNickname(nickname)
+	for _, word := range cfg.Blocklist {
+		if word = normalizeNickname(word); word != "" && strings.Contains(normalized, word) {
+			return ErrNicknameForbidden
+		}
+	}
+
+	return nil
+}
+
+// leetReplacer - common digit substitutions used to bypass the blocklist
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// normalizeNickname - lowercases nickname, undoes digit substitutions and drops separators
+func normalizeNickname(nickname string) string {
+	nickname = leetReplacer.Replace(strings.ToLower(nickname))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, nickname)
+}
@@ -0,0 +1,66 @@
+package characterservice
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Silverman143/character-service/internal/config"
+)
+
+func TestNormalizeNickname(t *testing.T) {
+	tests := []struct {
+		name     string
+		nickname string
+		want     string
+	}{
+		{name: "lowercase", nickname: "PlayerOne", want: "playerone"},
+		{name: "leet digits", nickname: "B4dW0rd", want: "badword"},
+		{name: "separators are dropped", nickname: "bad_w-o-r_d", want: "badword"},
+		{name: "symbols are replaced before drop", nickname: "$h@me", want: "shame"},
+		{name: "unreplaced digits stay", nickname: "player2", want: "player2"},
+		{name: "unicode letters", nickname: "Игрок", want: "игрок"},
+		{name: "empty", nickname: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeNickname(tt.nickname); got != tt.want {
+				t.Errorf("normalizeNickname(%q) = %q, want %q", tt.nickname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNickname(t *testing.T) {
+	c := &Character{cfg: &config.Config{Nicknames: config.NicknamesConfig{
+		MinLength: 3,
+		MaxLength: 10,
+		Blocklist: []string{"badword", "", "--"},
+	}}}
+
+	tests := []struct {
+		name     string
+		nickname string
+		wantErr  error
+	}{
+		{name: "valid", nickname: "player_1"},
+		{name: "min length", nickname: "abc"},
+		{name: "max length", nickname: "abcdefghij"},
+		{name: "length counts runes", nickname: "ИгрокИгрок"},
+		{name: "too short", nickname: "ab", wantErr: ErrInvalidNickname},
+		{name: "too long", nickname: "abcdefghijk", wantErr: ErrInvalidNickname},
+		{name: "space", nickname: "bad name", wantErr: ErrInvalidNickname},
+		{name: "symbol", nickname: "name!", wantErr: ErrInvalidNickname},
+		{name: "blocked word", nickname: "xbadwordx", wantErr: ErrNicknameForbidden},
+		{name: "blocked word with leet", nickname: "B4DW0RD", wantErr: ErrNicknameForbidden},
+		{name: "blocked word with separators", nickname: "bad_w-ord", wantErr: ErrNicknameForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.validateNickname(tt.nickname); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateNickname(%q) error = %v, want %v", tt.nickname, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// campaignsHorizon - campaigns starting within this period are cached together with running ones
+const campaignsHorizon = 24 * time.Hour
+
+// GetLevelUpQuote - returns price of the next level for the user with discount campaigns applied
+func (c *Character) GetLevelUpQuote(ctx context.Context, userID int64) (*dto.LevelUpQuoteDTO, error) {
+	const op = "services.character.GetLevelUpQuote"
+
+	level, err := c.GetCharacterLevel(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get character level: %w", op, err)
+	}
+
+	levelsPrices, err := c.GetLevelsPrices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get levels prices: %w", op, err)
+	}
+
+	nextLevelPrice, exists := levelsPrices.GetLevelPrice(*level + 1)
+	if !exists {
+		return nil, ErrNoNextLevel
+	}
+
+	price, err := c.quotePrice(ctx, userID, dto.PriceTargetLevelUp, nextLevelPrice.Level, nextLevelPrice.CoinsPrice)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.LevelUpQuoteDTO{
+		Level:                nextLevelPrice.Level,
+		PriceDTO:             price,
+		ReferralsPrice:       nextLevelPrice.ReferralsPrice,
+		ReferralsForFreeOpen: nextLevelPrice.ReferralsForFreeOpen,
+	}, nil
+}
+
+// quotePrice - applies the best running campaign for the user segment to the price
+func (c *Character) quotePrice(ctx context.Context, userID int64, target string, targetID int, price int64) (dto.PriceDTO, error) {
+	campaigns, err := c.getPriceCampaigns(ctx)
+	if err != nil {
+		return dto.PriceDTO{}, err
+	}
+
+	now := time.Now()
+	if !dto.HasRunning(campaigns, now, target) {
+		return dto.PriceDTO{OriginalPrice: price, Price: price}, nil
+	}
+
+	segment, err := c.priceSegment(ctx, userID)
+	if err != nil {
+		return dto.PriceDTO{}, err
+	}
+
+	return dto.BestPrice(campaigns, now, target, targetID, segment, price), nil
+}
+
+// applySkinPrices - replaces skin prices with discounted ones, original price is kept next to it.
+// On error prices stay original.
+func (c *Character) applySkinPrices(ctx context.Context, userID int64, skins *dto.GetSkinsDTO) {
+	for i := range skins.Skins {
+		skins.Skins[i].OriginalPrice = skins.Skins[i].Price
+	}
+
+	campaigns, err := c.getPriceCampaigns(ctx)
+	if err != nil {
+		c.log.Error("error with getting price campaigns", "error", err)
+		return
+	}
+
+	now := time.Now()
+	if !dto.HasRunning(campaigns, now, dto.PriceTargetSkin) {
+		return
+	}
+
+	segment, err := c.priceSegment(ctx, userID)
+	if err != nil {
+		c.log.Error("error with getting price segment", "userID", userID, "error", err)
+		return
+	}
+
+	for i := range skins.Skins {
+		skin := &skins.Skins[i]
+		price := dto.BestPrice(campaigns, now, dto.PriceTargetSkin, skin.ID, segment, skin.OriginalPrice)
+		skin.Price = price.Price
+		skin.CampaignID = price.CampaignID
+	}
+}
+
+// getPriceCampaigns - returns cached campaigns running now or starting soon.
+// Cache expires at the nearest campaign start or end, so discounts switch exactly at campaign boundaries.
+func (c *Character) getPriceCampaigns(ctx context.Context) ([]dto.PriceCampaignDTO, error) {
+	var campaigns []dto.PriceCampaignDTO
+
+	err := c.cache.Get(ctx, cachekeys.PriceCampaigns, &campaigns)
+	if err == nil {
+		return campaigns, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		c.log.Error("error with getting cached price campaigns", "error", err)
+	}
+
+	now := time.Now()
+	campaigns, err = c.pricingProvider.GetPriceCampaigns(ctx, now, now.Add(campaignsHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.cache.Set(ctx, cachekeys.PriceCampaigns, campaigns, campaignsTTL(campaigns, now)); err != nil {
+		c.log.Error("error with saving price campaigns in cache", "error", err)
+	}
+
+	return campaigns, nil
+}
+
+// campaignsTTL - time until the nearest campaign boundary, not longer than the loading horizon
+func campaignsTTL(campaigns []dto.PriceCampaignDTO, now time.Time) time.Duration {
+	ttl := campaignsHorizon
+	if next := dto.NextBoundary(campaigns, now); !next.IsZero() {
+		// Кампании в кэше все равно проверяются по времени, TTL лишь подтягивает новые кампании
+		ttl = min(ttl, max(next.Sub(now), time.Second))
+	}
+	return ttl
+}
+
+func (c *Character) priceSegment(ctx context.Context, userID int64) (dto.PriceSegmentDTO, error) {
+	ranking, err := c.characterProvider.GetCharacterRanking(ctx, userID)
+	if err != nil {
+		return dto.PriceSegmentDTO{}, err
+	}
+	return dto.PriceSegmentDTO{Level: ranking.Level, Prestige: ranking.Prestige}, nil
+}
+
+// ListPriceCampaigns - returns all campaigns for admin
+func (c *Character) ListPriceCampaigns(ctx context.Context) ([]dto.PriceCampaignDTO, error) {
+	const op = "services.character.ListPriceCampaigns"
+
+	campaigns, err := c.pricingProvider.GetAllPriceCampaigns(ctx)
+	if err != nil {
+		c.log.Error("Error with getting price campaigns", "op", op, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return campaigns, nil
+}
+
+// CreatePriceCampaign - adds discount campaign, it applies from its start time
+func (c *Character) CreatePriceCampaign(ctx context.Context, campaign dto.PriceCampaignDTO) (int, error) {
+	const op = "services.character.CreatePriceCampaign"
+
+	if err := validatePriceCampaign(campaign); err != nil {
+		return 0, err
+	}
+
+	campaignID, err := c.pricingProvider.CreatePriceCampaign(ctx, campaign)
+	if err != nil {
+		c.log.Error("Error with creating price campaign", "op", op, "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.invalidate(ctx, 0, cachekeys.MutationPriceCampaigns)
+
+	c.log.Info("price campaign created", "op", op, "campaignID", campaignID)
+	return campaignID, nil
+}
+
+// SetPriceCampaignActive - enables or disables discount campaign
+func (c *Character) SetPriceCampaignActive(ctx context.Context, campaignID int, isActive bool) error {
+	const op = "services.character.SetPriceCampaignActive"
+
+	if err := c.pricingProvider.SetPriceCampaignActive(ctx, campaignID, isActive); err != nil {
+		if errors.Is(err, storage.ErrPriceCampaignNotFound) {
+			return ErrPriceCampaignNotExist
+		}
+		c.log.Error("Error with changing price campaign status", "op", op, "campaignID", campaignID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.invalidate(ctx, 0, cachekeys.MutationPriceCampaigns)
+	return nil
+}
+
+func validatePriceCampaign(campaign dto.PriceCampaignDTO) error {
+	if campaign.Target != dto.PriceTargetLevelUp && campaign.Target != dto.PriceTargetSkin {
+		return ErrInvalidPriceCampaign
+	}
+	if !campaign.EndsAt.After(campaign.StartsAt) || campaign.DiscountValue <= 0 {
+		return ErrInvalidPriceCampaign
+	}
+
+	switch campaign.DiscountType {
+	case dto.DiscountPercent:
+		if campaign.DiscountValue > 100 {
+			return ErrInvalidPriceCampaign
+		}
+	case dto.DiscountFixed:
+	default:
+		return ErrInvalidPriceCampaign
+	}
+
+	return nil
+}
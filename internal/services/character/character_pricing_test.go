@@ -0,0 +1,35 @@
+package characterservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+)
+
+func TestCampaignsTTL(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	campaign := func(starts, ends time.Duration) dto.PriceCampaignDTO {
+		return dto.PriceCampaignDTO{StartsAt: now.Add(starts), EndsAt: now.Add(ends)}
+	}
+
+	tests := []struct {
+		name      string
+		campaigns []dto.PriceCampaignDTO
+		want      time.Duration
+	}{
+		{name: "no campaigns", want: campaignsHorizon},
+		{name: "expires at campaign end", campaigns: []dto.PriceCampaignDTO{campaign(-time.Hour, 2*time.Hour)}, want: 2 * time.Hour},
+		{name: "expires at campaign start", campaigns: []dto.PriceCampaignDTO{campaign(30*time.Minute, 2*time.Hour)}, want: 30 * time.Minute},
+		{name: "not longer than horizon", campaigns: []dto.PriceCampaignDTO{campaign(-time.Hour, 72*time.Hour)}, want: campaignsHorizon},
+		{name: "at least a second", campaigns: []dto.PriceCampaignDTO{campaign(-time.Hour, time.Millisecond)}, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := campaignsTTL(tt.campaigns, now); got != tt.want {
+				t.Errorf("campaignsTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
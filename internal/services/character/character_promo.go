@@ -0,0 +1,183 @@
+package characterservice
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+// promoCodeAlphabet - characters of generated codes without easily confused 0/O and 1/I
+const promoCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// promoCodeAttempts - generation rounds for codes that collided with existing ones
+const promoCodeAttempts = 3
+
+// RedeemPromoCode - checks promo code limits and grants its reward bundle
+func (c *Character) RedeemPromoCode(ctx context.Context, userID int64, code string) (*dto.PromoRedemptionDTO, error) {
+	const op = "services.character.RedeemPromoCode"
+	logger := c.log.With("op", op)
+
+	code = normalizePromoCode(code)
+	if code == "" {
+		return nil, ErrPromoCodeIsNotExist
+	}
+
+	promo, err := c.promoCodeProvider.GetPromoCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, storage.ErrPromoCodeNotFound) {
+			return nil, ErrPromoCodeIsNotExist
+		}
+		logger.Error("Error with getting promo code", "code", code, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !promo.IsActive {
+		return nil, ErrPromoCodeIsNotExist
+	}
+	if promo.IsExpired(time.Now()) {
+		return nil, ErrPromoCodeExpired
+	}
+
+	level, err := c.GetCharacterLevel(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if *level < promo.MinLevel {
+		return nil, ErrPromoCodeLevelTooLow
+	}
+
+	// Активация и все награды набора сохраняются вместе, монеты набора начисляются одним платежом после транзакции
+	var redemptionID int64
+	effects, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		var err error
+		redemptionID, err = tx.CreatePromoRedemption(ctx, *promo, userID)
+		if err != nil {
+			return err
+		}
+
+		for i, reward := range promo.Rewards {
+			if err := c.storeReward(ctx, tx, userID, reward, rewardSourcePromo, effects); err != nil {
+				return fmt.Errorf("reward %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrPromoCodeExhausted):
+			return nil, ErrPromoCodeExhausted
+		case errors.Is(err, storage.ErrPromoCodeUserLimit):
+			return nil, ErrPromoCodeAlreadyRedeemed
+		}
+		logger.Error("Error with redeeming promo code", "userID", userID, "code", code, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.payCoins(ctx, userID, effects); err != nil {
+		logger.Error("Error with granting promo reward", "userID", userID, "code", code, "error", err)
+		// Откатываем активацию только если пользователь еще ничего не получил, иначе повторная активация выдаст награды дважды
+		if !effects.stored {
+			if delErr := c.promoCodeProvider.DeletePromoRedemption(ctx, redemptionID); delErr != nil {
+				logger.Error("Error with removing promo redemption", "userID", userID, "code", code, "error", delErr)
+			}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.Info("promo code redeemed", "userID", userID, "code", code)
+	return &dto.PromoRedemptionDTO{Code: code, Rewards: promo.Rewards}, nil
+}
+
+// GeneratePromoCodes - creates batch of unique random codes with the same limits and rewards, used by admins
+func (c *Character) GeneratePromoCodes(ctx context.Context, batch dto.PromoCodeBatchDTO) ([]string, error) {
+	const op = "services.character.GeneratePromoCodes"
+	logger := c.log.With("op", op)
+
+	cfg := c.cfg.PromoCodes
+
+	if batch.Count <= 0 || batch.Count > cfg.MaxBatchSize || len(batch.Rewards) == 0 {
+		return nil, ErrInvalidPromoCodeBatch
+	}
+	if batch.MaxRedemptions != nil && *batch.MaxRedemptions <= 0 {
+		return nil, ErrInvalidPromoCodeBatch
+	}
+	for _, reward := range batch.Rewards {
+		if err := validateReward(reward); err != nil {
+			return nil, err
+		}
+	}
+
+	if batch.BatchID == "" {
+		batch.BatchID = uuid.New().String()
+	}
+	if batch.PerUserLimit <= 0 {
+		batch.PerUserLimit = 1
+	}
+	if batch.MinLevel <= 0 {
+		batch.MinLevel = 1
+	}
+	batch.Prefix = normalizePromoCode(batch.Prefix)
+
+	codes := make([]string, 0, batch.Count)
+	for attempt := 0; attempt < promoCodeAttempts && len(codes) < batch.Count; attempt++ {
+		candidates, err := generatePromoCodes(batch.Prefix, cfg.CodeLength, batch.Count-len(codes))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		// Коды, совпавшие с уже существующими, пропускаются и генерируются заново
+		saved, err := c.promoCodeProvider.CreatePromoCodes(ctx, batch, candidates)
+		if err != nil {
+			logger.Error("Error with saving promo codes", "batchID", batch.BatchID, "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		codes = append(codes, saved...)
+	}
+
+	if len(codes) < batch.Count {
+		logger.Warn("not all promo codes were generated", "batchID", batch.BatchID, "requested", batch.Count, "generated", len(codes))
+	}
+
+	logger.Info("promo codes generated", "batchID", batch.BatchID, "count", len(codes))
+	return codes, nil
+}
+
+// generatePromoCodes - returns count random codes, duplicates inside the batch are excluded
+func generatePromoCodes(prefix string, length int, count int) ([]string, error) {
+	alphabetSize := big.NewInt(int64(len(promoCodeAlphabet)))
+
+	unique := make(map[string]struct{}, count)
+	codes := make([]string, 0, count)
+	for len(codes) < count {
+		var b strings.Builder
+		b.WriteString(prefix)
+		for i := 0; i < length; i++ {
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate promo code: %w", err)
+			}
+			b.WriteByte(promoCodeAlphabet[n.Int64()])
+		}
+
+		code := b.String()
+		if _, ok := unique[code]; ok {
+			continue
+		}
+		unique[code] = struct{}{}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// normalizePromoCode - codes are stored in upper case, users may type them in any case
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
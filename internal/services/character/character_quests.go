@@ -0,0 +1,221 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// ListQuests - returns daily and weekly quests of the user, assigning them on the first request of the period
+func (c *Character) ListQuests(ctx context.Context, userID int64) ([]dto.QuestDTO, error) {
+	const op = "services.character.ListQuests"
+	logger := c.log.With("op", op)
+
+	cachekey := questsCacheKey(userID)
+
+	var quests []dto.QuestDTO
+	err := c.cache.Get(ctx, cachekey, &quests)
+	if err == nil {
+		return quests, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		logger.Error("error with getting cached quests", "userID", userID, "error", err)
+	}
+
+	if err := c.assignQuests(ctx, userID); err != nil {
+		logger.Error("Error with assigning quests", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	quests, err = c.questProvider.GetActiveQuests(ctx, userID)
+	if err != nil {
+		logger.Error("Error with getting quests", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.cache.Set(ctx, cachekey, quests, c.cfg.Redis.Lifetime); err != nil {
+		logger.Error("error with saving quests in cache", "userID", userID, "error", err)
+	}
+
+	return quests, nil
+}
+
+// ClaimQuest - grants reward of the completed quest once
+func (c *Character) ClaimQuest(ctx context.Context, userID int64, questID int64) (*dto.RewardDTO, error) {
+	const op = "services.character.ClaimQuest"
+	logger := c.log.With("op", op)
+
+	var reward *dto.RewardDTO
+	effects, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		var err error
+		reward, err = tx.ClaimQuest(ctx, userID, questID)
+		if err != nil {
+			return err
+		}
+		if reward == nil {
+			return ErrQuestNotClaimable
+		}
+
+		effects.mutations = append(effects.mutations, cachekeys.MutationQuests)
+		return c.storeReward(ctx, tx, userID, *reward, rewardSourceQuest, effects)
+	})
+	if err != nil {
+		if errors.Is(err, ErrQuestNotClaimable) {
+			return nil, err
+		}
+		logger.Error("Error with claiming quest", "userID", userID, "questID", questID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.payCoins(ctx, userID, effects); err != nil {
+		logger.Error("Error with granting quest reward", "userID", userID, "questID", questID, "error", err)
+		if !effects.stored {
+			if unclaimErr := c.questProvider.UnclaimQuest(ctx, userID, questID); unclaimErr != nil {
+				logger.Error("Error with resetting quest claim", "userID", userID, "questID", questID, "error", unclaimErr)
+			}
+			c.invalidate(ctx, userID, cachekeys.MutationQuests)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.Info("quest claimed", "userID", userID, "questID", questID)
+	return reward, nil
+}
+
+// trackQuestProgress - advances quests of the user tracking the action.
+// Errors are only logged, quests must not break the action itself.
+func (c *Character) trackQuestProgress(ctx context.Context, userID int64, action string, amount int) {
+	logger := c.log.With("op", "services.character.trackQuestProgress")
+
+	if amount <= 0 {
+		return
+	}
+
+	// Задания выдаются при первом действии периода, даже если список еще не запрашивали
+	if err := c.assignQuests(ctx, userID); err != nil {
+		logger.Error("Error with assigning quests", "userID", userID, "error", err)
+		return
+	}
+
+	updated, err := c.questProvider.AddQuestProgress(ctx, userID, action, amount)
+	if err != nil {
+		logger.Error("Error with adding quest progress", "userID", userID, "action", action, "error", err)
+		return
+	}
+
+	if updated > 0 {
+		c.invalidate(ctx, userID, cachekeys.MutationQuests)
+	}
+}
+
+// assignQuests - assigns quests of the current periods once per UTC day, later calls of the day only check the marker.
+// Weekly periods start on a day boundary too, so the daily marker covers them.
+func (c *Character) assignQuests(ctx context.Context, userID int64) error {
+	now := time.Now().UTC()
+	marker := cachekeys.QuestsAssigned(userID, now.Format(time.DateOnly))
+
+	assigned, err := c.cache.Exists(ctx, marker)
+	if err != nil {
+		c.log.Error("error with checking quests marker", "userID", userID, "error", err)
+	} else if *assigned > 0 {
+		return nil
+	}
+
+	if err := c.questProvider.AssignQuests(ctx, userID); err != nil {
+		return err
+	}
+
+	// Маркер живет до конца суток UTC, в новых сутках задания выдаются заново
+	untilNextDay := now.Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+	if err := c.cache.SetInt(ctx, marker, 1, untilNextDay); err != nil {
+		c.log.Error("error with saving quests marker", "userID", userID, "error", err)
+	}
+	return nil
+}
+
+// questsCacheKey - quests list changes with the day, so the key includes current UTC date
+func questsCacheKey(userID int64) string {
+	return cachekeys.ActiveQuests(userID, time.Now().UTC().Format(time.DateOnly))
+}
+
+// ListQuestTemplates - returns all quest templates for admin
+func (c *Character) ListQuestTemplates(ctx context.Context) ([]dto.QuestTemplateDTO, error) {
+	const op = "services.character.ListQuestTemplates"
+
+	templates, err := c.questProvider.GetQuestTemplates(ctx)
+	if err != nil {
+		c.log.Error("Error with getting quest templates", "op", op, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return templates, nil
+}
+
+// CreateQuestTemplate - adds quest template, it is assigned to characters from their next quests request
+func (c *Character) CreateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) (int, error) {
+	const op = "services.character.CreateQuestTemplate"
+
+	if err := validateQuestTemplate(template); err != nil {
+		return 0, err
+	}
+
+	templateID, err := c.questProvider.CreateQuestTemplate(ctx, template)
+	if err != nil {
+		c.log.Error("Error with creating quest template", "op", op, "code", template.Code, "error", err)
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("quest template created", "op", op, "templateID", templateID)
+	return templateID, nil
+}
+
+// UpdateQuestTemplate - changes quest template, quests assigned in the current period keep their target
+func (c *Character) UpdateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) error {
+	const op = "services.character.UpdateQuestTemplate"
+
+	if err := validateQuestTemplate(template); err != nil {
+		return err
+	}
+
+	if err := c.questProvider.UpdateQuestTemplate(ctx, template); err != nil {
+		if errors.Is(err, storage.ErrQuestTemplateNotFound) {
+			return ErrQuestTemplateNotExist
+		}
+		c.log.Error("Error with updating quest template", "op", op, "templateID", template.ID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetQuestTemplateActive - enables or disables quest template, disabled template is not assigned from the next period
+func (c *Character) SetQuestTemplateActive(ctx context.Context, templateID int, isActive bool) error {
+	const op = "services.character.SetQuestTemplateActive"
+
+	if err := c.questProvider.SetQuestTemplateActive(ctx, templateID, isActive); err != nil {
+		if errors.Is(err, storage.ErrQuestTemplateNotFound) {
+			return ErrQuestTemplateNotExist
+		}
+		c.log.Error("Error with changing quest template status", "op", op, "templateID", templateID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func validateQuestTemplate(template dto.QuestTemplateDTO) error {
+	if template.Period != dto.QuestPeriodDaily && template.Period != dto.QuestPeriodWeekly {
+		return ErrInvalidQuestTemplate
+	}
+	if !dto.IsQuestAction(template.Action) || template.Target <= 0 || template.Code == "" {
+		return ErrInvalidQuestTemplate
+	}
+
+	return validateReward(template.RewardDTO)
+}
@@ -0,0 +1,68 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/Silverman143/character-service/internal/config"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// questsStub - quest provider counting assignments and progress updates
+type questsStub struct {
+	storage.IQuestProvider
+	assignErr error
+	assigned  int
+	progress  int
+}
+
+func (s *questsStub) AssignQuests(context.Context, int64) error {
+	s.assigned++
+	return s.assignErr
+}
+
+func (s *questsStub) AddQuestProgress(context.Context, int64, string, int) (int64, error) {
+	s.progress++
+	return 0, nil
+}
+
+func TestTrackQuestProgressAssignsOncePerDay(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		assignErr    error
+		actions      int
+		wantAssigned int
+		wantProgress int
+	}{
+		{name: "assigned on the first action only", actions: 3, wantAssigned: 1, wantProgress: 3},
+		{name: "failed assignment is retried", assignErr: errors.New("database is down"), actions: 3, wantAssigned: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quests := &questsStub{assignErr: tt.assignErr}
+			c := &Character{
+				log:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+				cfg:           &config.Config{},
+				questProvider: quests,
+				cache:         cache.NewMemoryCache(config.MemoryCacheConfig{Size: 10}),
+			}
+
+			for i := 0; i < tt.actions; i++ {
+				c.trackQuestProgress(ctx, testUserID, dto.QuestActionGameFinished, 1)
+			}
+
+			if quests.assigned != tt.wantAssigned || quests.progress != tt.wantProgress {
+				t.Errorf("assigned %d times, progress updated %d times, want %d and %d",
+					quests.assigned, quests.progress, tt.wantAssigned, tt.wantProgress)
+			}
+		})
+	}
+}
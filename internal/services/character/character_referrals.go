@@ -0,0 +1,136 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// AddReferral - counts new referral of the user and grants reached referral milestones.
+// Referral, milestones and their rewards are saved in one transaction, coins are paid after it commits.
+func (c *Character) AddReferral(ctx context.Context, userID int64, referralUserID int64) error {
+	const op = "services.character.AddReferral"
+	logger := c.log.With("op", op)
+
+	// Значение referral service запрашиваем до транзакции, ее тело может выполниться повторно
+	remote, err := c.remoteReferralsCount(ctx, userID)
+	if err != nil {
+		logger.Error("Error with getting referrals count", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		added             bool
+		granted, unstored []int
+	)
+	effects, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		count, isNew, err := tx.AddReferral(ctx, userID, referralUserID)
+		if err != nil {
+			return err
+		}
+		added = isNew
+
+		// Несверенный счетчик поднимается до значения referral service, которое уже включает этого реферала
+		if remote != nil {
+			if count, err = tx.SyncReferralsCount(ctx, userID, *remote); err != nil {
+				return err
+			}
+		}
+
+		// Проверяем вехи и для повторного события: прошлая выдача могла не пройти
+		granted, unstored, err = c.grantReferralMilestones(ctx, tx, userID, count, effects)
+		return err
+	})
+	if err != nil {
+		logger.Error("Error with adding referral", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !added {
+		logger.Info("referral already counted", "userID", userID, "referralUserID", referralUserID)
+	}
+
+	if err := c.payCoins(ctx, userID, effects); err != nil {
+		logger.Error("Error with granting milestone rewards", "userID", userID, "error", err)
+		// Вехи, награда которых состояла только из монет, снимаем, чтобы следующее событие выдало их снова
+		for _, referrals := range unstored {
+			if delErr := c.referralProvider.DeleteReferralMilestone(ctx, userID, referrals); delErr != nil {
+				logger.Error("Error with removing milestone", "userID", userID, "referrals", referrals, "error", delErr)
+			}
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(granted) > 0 {
+		logger.Info("referral milestones granted", "userID", userID, "referrals", granted)
+	}
+	return nil
+}
+
+// grantReferralMilestones - grants in the transaction every configured milestone reached with count, each only once.
+// Returns granted milestones and those of them whose rewards were not written to storage and are paid only with coins.
+func (c *Character) grantReferralMilestones(ctx context.Context, tx storage.TxRepository, userID int64, count int, effects *commitEffects) (granted, unstored []int, err error) {
+	for _, milestone := range c.cfg.ReferralMilestones {
+		if milestone.Referrals > count {
+			continue
+		}
+
+		created, err := tx.CreateReferralMilestone(ctx, userID, milestone.Referrals)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !created {
+			continue
+		}
+
+		// Признак записи считаем по каждой вехе отдельно
+		stored := effects.stored
+		effects.stored = false
+		err = c.storeReward(ctx, tx, userID, dto.NewRewardDTO(milestone.Reward), rewardSourceReferralMilestone, effects)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to grant milestone %d reward: %w", milestone.Referrals, err)
+		}
+		granted = append(granted, milestone.Referrals)
+		if !effects.stored {
+			unstored = append(unstored, milestone.Referrals)
+		}
+		effects.stored = effects.stored || stored
+	}
+
+	return granted, unstored, nil
+}
+
+// remoteReferralsCount - returns referrals count from referral service when local counter was never synced with it
+func (c *Character) remoteReferralsCount(ctx context.Context, userID int64) (*int, error) {
+	_, synced, err := c.referralProvider.GetReferralsCount(ctx, userID)
+	if err != nil || synced {
+		return nil, err
+	}
+
+	remote, err := c.referralClient.GetReferralsAmount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &remote, nil
+}
+
+// getReferralsCount - returns local referrals counter, synced once with referral service
+// for characters created before the counter existed
+func (c *Character) getReferralsCount(ctx context.Context, userID int64) (int, error) {
+	count, synced, err := c.referralProvider.GetReferralsCount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if synced {
+		return count, nil
+	}
+
+	remote, err := c.referralClient.GetReferralsAmount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.referralProvider.SyncReferralsCount(ctx, userID, remote)
+}
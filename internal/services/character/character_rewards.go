@@ -0,0 +1,114 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+const (
+	rewardSourceSeason = "season"
+	rewardSourceReferralMilestone = "referral_milestone"
+	rewardSourceDaily = "daily"
+	rewardSourceQuest = "quest"
+	rewardSourcePromo = "promo"
+)
+
+// storeReward - writes reward to storage in the transaction, coins and cache changes are collected into effects
+func (c *Character) storeReward(ctx context.Context, tx storage.TxRepository, userID int64, reward dto.RewardDTO, source string, effects *commitEffects) error {
+	switch reward.Type {
+	case dto.RewardCoins:
+		effects.coins += reward.Amount
+
+	case dto.RewardBoost:
+		boost := dto.BoostDTO{
+			Type:      reward.BoostType,
+			Percent:   int(reward.Amount),
+			ExpiresAt: time.Now().Add(time.Duration(reward.DurationMinutes) * time.Minute),
+		}
+		if err := tx.AddBoost(ctx, userID, boost, source); err != nil {
+			return fmt.Errorf("failed to add boost: %w", err)
+		}
+		effects.mutations = append(effects.mutations, cachekeys.MutationBoosts)
+		effects.stored = true
+
+	case dto.RewardSkin:
+		if reward.SkinID == nil {
+			return ErrSkinIsNotExist
+		}
+		granted, err := c.grantSkin(ctx, tx, userID, *reward.SkinID, source, effects)
+		if err != nil {
+			return fmt.Errorf("failed to grant skin: %w", err)
+		}
+		effects.stored = effects.stored || granted
+
+	case dto.RewardLevel:
+		level, levels, err := c.reachableLevels(ctx, tx, userID, reward.Amount)
+		if err != nil {
+			return err
+		}
+		if levels == 0 {
+			return nil
+		}
+		for i := 0; i < levels; i++ {
+			if _, err := tx.UpgradeCharacterLevel(ctx, userID, level+i); err != nil {
+				return fmt.Errorf("failed to upgrade character level: %w", err)
+			}
+		}
+		effects.mutations = append(effects.mutations, cachekeys.MutationLevel)
+		effects.levelUp = true
+		effects.stored = true
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownReward, reward.Type)
+	}
+
+	return nil
+}
+
+// reachableLevels - returns current level and rewarded levels count clamped, so character is not upgraded
+// above the max level
+func (c *Character) reachableLevels(ctx context.Context, tx storage.TxRepository, userID int64, amount int64) (int, int, error) {
+	level, err := tx.GetCharacterLevel(ctx, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get character level: %w", err)
+	}
+
+	prices, err := c.GetLevelsPrices(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get level prices: %w", err)
+	}
+
+	return *level, clampLevels(*level, prices.MaxLevel(), amount), nil
+}
+
+// clampLevels - levels count from amount that can be added to level without passing maxLevel
+func clampLevels(level, maxLevel int, amount int64) int {
+	left := maxLevel - level
+	if left <= 0 || amount <= 0 {
+		return 0
+	}
+	return int(min(amount, int64(left)))
+}
+
+// validateReward - checks that reward configured by admin can be granted
+func validateReward(reward dto.RewardDTO) error {
+	switch reward.Type {
+	case dto.RewardCoins, dto.RewardBoost, dto.RewardLevel:
+		if reward.Amount <= 0 {
+			return ErrInvalidReward
+		}
+	case dto.RewardSkin:
+		if reward.SkinID == nil {
+			return ErrInvalidReward
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownReward, reward.Type)
+	}
+
+	return nil
+}
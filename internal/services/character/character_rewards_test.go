@@ -0,0 +1,30 @@
+package characterservice
+
+import "testing"
+
+func TestClampLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		maxLevel int
+		amount   int64
+		want     int
+	}{
+		{name: "below max", level: 3, maxLevel: 10, amount: 2, want: 2},
+		{name: "up to max", level: 3, maxLevel: 10, amount: 7, want: 7},
+		{name: "clamped to max", level: 3, maxLevel: 10, amount: 100, want: 7},
+		{name: "already at max", level: 10, maxLevel: 10, amount: 1, want: 0},
+		{name: "above max", level: 12, maxLevel: 10, amount: 1, want: 0},
+		{name: "zero amount", level: 3, maxLevel: 10, amount: 0, want: 0},
+		{name: "negative amount", level: 3, maxLevel: 10, amount: -5, want: 0},
+		{name: "amount above int range", level: 1, maxLevel: 10, amount: 1 << 40, want: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampLevels(tt.level, tt.maxLevel, tt.amount); got != tt.want {
+				t.Errorf("clampLevels(%d, %d, %d) = %d, want %d", tt.level, tt.maxLevel, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
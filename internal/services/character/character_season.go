@@ -0,0 +1,278 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// GetSeasonProgress - returns user progress in the active season with claim status of every tier
+func (c *Character) GetSeasonProgress(ctx context.Context, userID int64) (*dto.SeasonProgressInfoDTO, error) {
+	const op = "services.character.GetSeasonProgress"
+	logger := c.log.With("op", op)
+
+	season, err := c.getActiveSeason(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	progress, err := c.seasonProvider.GetSeasonProgress(ctx, season.ID, userID)
+	if err != nil {
+		logger.Error("Error with getting season progress", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	claims, err := c.seasonProvider.GetSeasonClaims(ctx, season.ID, userID)
+	if err != nil {
+		logger.Error("Error with getting season claims", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	info := &dto.SeasonProgressInfoDTO{
+		Season:      *season,
+		XP:          progress.XP,
+		IsPremium:   progress.IsPremium,
+		CurrentTier: season.CurrentTier(progress.XP),
+		Tiers:       make([]dto.SeasonTierStatusDTO, len(season.Tiers)),
+	}
+
+	for i, tier := range season.Tiers {
+		status := dto.SeasonTierStatusDTO{SeasonTierDTO: tier, Reached: progress.XP >= tier.XPRequired}
+		for _, claim := range claims {
+			if claim.TierNumber != tier.Number {
+				continue
+			}
+			if claim.IsPremium {
+				status.PremiumClaimed = true
+			} else {
+				status.FreeClaimed = true
+			}
+		}
+		info.Tiers[i] = status
+	}
+
+	return info, nil
+}
+
+// ClaimSeasonTier - grants free or premium reward of the reached season tier
+func (c *Character) ClaimSeasonTier(ctx context.Context, userID int64, tierNumber int, isPremium bool) (*dto.RewardDTO, error) {
+	const op = "services.character.ClaimSeasonTier"
+	logger := c.log.With("op", op)
+
+	season, err := c.getActiveSeason(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tier, ok := season.GetTier(tierNumber)
+	if !ok {
+		return nil, ErrSeasonTierNotExist
+	}
+
+	reward := tier.FreeReward
+	if isPremium {
+		reward = tier.PremiumReward
+	}
+	if reward == nil {
+		return nil, ErrSeasonTierNotExist
+	}
+
+	// Опыт и премиум могли измениться только что, проверяем по основной базе
+	progress, err := c.seasonProvider.GetSeasonProgress(storage.WithPrimary(ctx), season.ID, userID)
+	if err != nil {
+		logger.Error("Error with getting season progress", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if progress.XP < tier.XPRequired {
+		return nil, ErrSeasonTierNotReached
+	}
+	if isPremium && !progress.IsPremium {
+		return nil, ErrSeasonPremiumRequired
+	}
+
+	effects, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		created, err := tx.CreateSeasonClaim(ctx, season.ID, userID, tierNumber, isPremium)
+		if err != nil {
+			return err
+		}
+		if !created {
+			return ErrSeasonTierAlreadyClaimed
+		}
+		return c.storeReward(ctx, tx, userID, *reward, rewardSourceSeason, effects)
+	})
+	if err != nil {
+		if errors.Is(err, ErrSeasonTierAlreadyClaimed) {
+			return nil, err
+		}
+		logger.Error("Error with claiming season tier", "userID", userID, "tier", tierNumber, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.payCoins(ctx, userID, effects); err != nil {
+		logger.Error("Error with granting season reward", "userID", userID, "tier", tierNumber, "error", err)
+		if !effects.stored {
+			if delErr := c.seasonProvider.DeleteSeasonClaim(ctx, season.ID, userID, tierNumber, isPremium); delErr != nil {
+				logger.Error("Error with removing season claim", "userID", userID, "tier", tierNumber, "error", delErr)
+			}
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	logger.Info("season tier claimed", "userID", userID, "seasonID", season.ID, "tier", tierNumber, "premium", isPremium)
+	return reward, nil
+}
+
+// BuySeasonPremium - unlocks premium track of the active season for coins
+func (c *Character) BuySeasonPremium(ctx context.Context, userID int64) error {
+	const op = "services.character.BuySeasonPremium"
+	logger := c.log.With("op", op)
+
+	season, err := c.getActiveSeason(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if season.PremiumFree {
+		if _, err := c.setSeasonPremium(ctx, season.ID, userID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+	// Нулевая цена без флага бесплатного премиума - незаданная цена, а не раздача
+	if season.PremiumPrice <= 0 {
+		logger.Error("season premium price is not set", "seasonID", season.ID)
+		return ErrSeasonPremiumUnavailable
+	}
+
+	if _, err := c.assessFraud(ctx, userID, dto.FraudOperationSeasonPremium); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	paymentID := uuid.New().String()
+	if err := c.userClient.InitiatePayment(ctx, userID, season.PremiumPrice, paymentID); err != nil {
+		logger.Error("Error with initiating payment", "userID", userID, "error", err)
+		return fmt.Errorf("%s: failed to initiate payment: %w", op, err)
+	}
+
+	updated, err := c.setSeasonPremium(ctx, season.ID, userID)
+	if err != nil || !updated {
+		c.rollbackPayment(ctx, userID, paymentID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return ErrSeasonPremiumAlreadyActive
+	}
+
+	if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+		if unsetErr := c.seasonProvider.UnsetSeasonPremium(ctx, season.ID, userID); unsetErr != nil {
+			logger.Error("Error with disabling season premium", "userID", userID, "error", unsetErr)
+		}
+		return fmt.Errorf("%s: failed to finalize payment: %w", op, err)
+	}
+	c.recordOperation(ctx, userID, dto.FraudOperationSeasonPremium)
+
+	logger.Info("season premium bought", "userID", userID, "seasonID", season.ID)
+	return nil
+}
+
+// setSeasonPremium - enables premium track of the season in the transaction, returns false if it was already enabled
+func (c *Character) setSeasonPremium(ctx context.Context, seasonID int, userID int64) (bool, error) {
+	var updated bool
+	_, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		var err error
+		updated, err = tx.SetSeasonPremium(ctx, seasonID, userID)
+		return err
+	})
+	return updated, err
+}
+
+// AddSeasonXP - adds xp to the user in the active season, does nothing when there is no active season
+func (c *Character) AddSeasonXP(ctx context.Context, userID int64, xp int64) error {
+	const op = "services.character.AddSeasonXP"
+
+	if xp <= 0 {
+		return nil
+	}
+
+	season, err := c.getActiveSeason(ctx)
+	if err != nil {
+		if errors.Is(err, ErrNoActiveSeason) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.seasonProvider.AddSeasonXP(ctx, season.ID, userID, xp); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecordGamePlayed - adds season xp and quests progress for finished game
+func (c *Character) RecordGamePlayed(ctx context.Context, userID int64) error {
+	c.trackQuestProgress(ctx, userID, dto.QuestActionGameFinished, 1)
+
+	return c.AddSeasonXP(ctx, userID, c.cfg.Seasons.XPPerGame)
+}
+
+// RolloverSeasons - finishes ended seasons and starts scheduled ones
+func (c *Character) RolloverSeasons(ctx context.Context) error {
+	const op = "services.character.RolloverSeasons"
+	logger := c.log.With("op", op)
+
+	changed, err := c.seasonProvider.RolloverSeasons(ctx, time.Now())
+	if err != nil {
+		logger.Error("Error with seasons rollover", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if changed == 0 {
+		return nil
+	}
+
+	c.invalidate(ctx, 0, cachekeys.MutationSeasons)
+
+	logger.Info("seasons rolled over", "changed", changed)
+	return nil
+}
+
+func (c *Character) getActiveSeason(ctx context.Context) (*dto.SeasonDTO, error) {
+	var season dto.SeasonDTO
+
+	err := c.cache.Get(ctx, cachekeys.ActiveSeason, &season)
+	if err == nil {
+		if time.Now().Before(season.EndsAt) {
+			return &season, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		c.log.Error("error with getting cached active season", "error", err)
+	}
+
+	activeSeason, err := c.seasonProvider.GetActiveSeason(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrSeasonNotFound) {
+			return nil, ErrNoActiveSeason
+		}
+		return nil, err
+	}
+
+	if !time.Now().Before(activeSeason.EndsAt) {
+		// Сезон закончился, но воркер еще не перевел его в finished
+		return nil, ErrNoActiveSeason
+	}
+
+	if err := c.cache.Set(ctx, cachekeys.ActiveSeason, activeSeason, c.cfg.Redis.Lifetime); err != nil {
+		c.log.Error("error with saving active season in cache", "error", err)
+	}
+
+	return activeSeason, nil
+}
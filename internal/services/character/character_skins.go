@@ -0,0 +1,229 @@
+package characterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kafkaproducer "github.com/Silverman143/character-service/internal/kafka/producer"
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	skinSourcePurchase = "purchase"
+	skinSourceGift     = "gift"
+)
+
+// BuySkin - buys skin for coins, limited skins are checked for availability and supply
+func (c *Character) BuySkin(ctx context.Context, userID int64, skinID int) error {
+	const op = "services.character.BuySkin"
+	logger := c.log.With("op", op)
+
+	skins, err := c.GetSkins(ctx, userID)
+	if err != nil {
+		logger.Error("Error getting user skins", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	skin, ok := skins.GetSkin(skinID)
+	if !ok {
+		return ErrSkinIsNotExist
+	}
+	if skin.IsOpened {
+		return ErrSkinAlreadyOwned
+	}
+	if !skin.IsAvailable {
+		return ErrSkinUnavailable
+	}
+	// Эксклюзивные скины выдаются только наградами сезонов, рефералов и промокодов
+	if skin.IsExclusive {
+		return ErrSkinNotPurchasable
+	}
+
+	if _, err := c.assessFraud(ctx, userID, dto.FraudOperationSkinPurchase); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	paymentID := uuid.New().String()
+	if err := c.userClient.InitiatePayment(ctx, userID, skin.Price, paymentID); err != nil {
+		logger.Error("Error with initiating payment", "userID", userID, "error", err)
+		return fmt.Errorf("%s: failed to initiate payment: %w", op, err)
+	}
+
+	_, err = c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		granted, err := c.grantSkin(ctx, tx, userID, skinID, skinSourcePurchase, effects)
+		if err == nil && !granted {
+			err = ErrSkinAlreadyOwned
+		}
+		return err
+	})
+	if err != nil {
+		c.rollbackPayment(ctx, userID, paymentID)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+		return fmt.Errorf("%s: failed to finalize payment: %w", op, err)
+	}
+	c.recordOperation(ctx, userID, dto.FraudOperationSkinPurchase)
+
+	logger.Info("skin bought", "userID", userID, "skinID", skinID)
+	return nil
+}
+
+// grantSkin - gives skin to the user in the transaction, returns false if user already owned it.
+// Sold out event is collected into effects when the last copy of limited skin is taken.
+func (c *Character) grantSkin(ctx context.Context, tx storage.TxRepository, userID int64, skinID int, source string, effects *commitEffects) (bool, error) {
+	// Заработанные награды выдаются и после окончания продаж скина, тираж тратят только покупки и подарки
+	sale := source == skinSourcePurchase || source == skinSourceGift
+	grant, err := tx.GrantSkin(ctx, userID, skinID, source, sale)
+	if err != nil {
+		if errors.Is(err, storage.ErrSkinUnavailable) {
+			return false, ErrSkinUnavailable
+		}
+		return false, err
+	}
+	if !grant.Granted {
+		return false, nil
+	}
+
+	effects.mutations = append(effects.mutations, cachekeys.MutationOwnedSkins)
+	if grant.MaxSupply != nil {
+		// Остаток тиража изменился, каталог в кэше устарел
+		effects.mutations = append(effects.mutations, cachekeys.MutationSkinsCatalog)
+	}
+
+	if grant.SoldOut {
+		effects.soldOut = append(effects.soldOut, kafkaproducer.SkinSoldOutEvent{
+			Type:      kafkaproducer.EventSkinSoldOut,
+			SkinID:    skinID,
+			MaxSupply: *grant.MaxSupply,
+			SoldOutAt: time.Now(),
+		})
+	}
+
+	return true, nil
+}
+
+// GiftSkin - buys skin on behalf of fromUserID and gives it to toUserID
+// Not exposed over gRPC yet: protos_chadnaldo v0.0.45 has no RPC for it.
+func (c *Character) GiftSkin(ctx context.Context, fromUserID, toUserID int64, skinID int) error {
+	const op = "services.character.GiftSkin"
+	logger := c.log.With("op", op)
+
+	if fromUserID == toUserID {
+		return ErrSelfGift
+	}
+
+	// Получатель мог только что создать персонажа, реплика его еще не видит
+	if _, err := c.characterProvider.GetCharacterLevel(storage.WithPrimary(ctx), toUserID); err != nil {
+		if errors.Is(err, storage.ErrCharacterNotFound) {
+			return ErrGiftRecipientNotFound
+		}
+		logger.Error("Error getting recipient character", "toUserID", toUserID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	skins, err := c.GetSkins(ctx, toUserID)
+	if err != nil {
+		logger.Error("Error getting recipient skins", "toUserID", toUserID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	skin, ok := skins.GetSkin(skinID)
+	if !ok {
+		return ErrSkinIsNotExist
+	}
+	if skin.IsOpened {
+		return ErrSkinAlreadyOwned
+	}
+	if !skin.IsAvailable {
+		return ErrSkinUnavailable
+	}
+	// Эксклюзивные скины выдаются только наградами сезонов, рефералов и промокодов
+	if skin.IsExclusive {
+		return ErrSkinNotPurchasable
+	}
+
+	if _, err := c.assessFraud(ctx, fromUserID, dto.FraudOperationSkinGift); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Цена скина для дарителя: скидки считаются по его сегменту, а не по сегменту получателя
+	price, err := c.quotePrice(ctx, fromUserID, dto.PriceTargetSkin, skinID, skin.OriginalPrice)
+	if err != nil {
+		logger.Error("Error with getting skin price", "fromUserID", fromUserID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Лимит подарков считаем атомарно в Redis, отказ возвращает слот обратно
+	limitKey := cachekeys.SkinGiftsDaily(fromUserID, time.Now().UTC().Format(time.DateOnly))
+	releaseLimit := func() {
+		if err := c.cache.Decr(ctx, limitKey); err != nil {
+			logger.Error("failed to release daily gift slot", "fromUserID", fromUserID, "error", err)
+		}
+	}
+
+	sent, err := c.cache.Incr(ctx, limitKey, 24*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if sent > c.cfg.Gifts.DailyLimit {
+		releaseLimit()
+		return ErrGiftDailyLimitReached
+	}
+
+	paymentID := uuid.New().String()
+	if err := c.userClient.InitiatePayment(ctx, fromUserID, price.Price, paymentID); err != nil {
+		releaseLimit()
+		logger.Error("Error with initiating payment", "fromUserID", fromUserID, "error", err)
+		return fmt.Errorf("%s: failed to initiate payment: %w", op, err)
+	}
+
+	// Скин и запись о подарке сохраняются вместе, иначе оплаченный подарок может остаться без истории
+	gift := dto.SkinGiftDTO{
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		SkinID:     skinID,
+		Price:      price.Price,
+		PaymentID:  paymentID,
+	}
+	_, err = c.inTx(ctx, toUserID, func(tx storage.TxRepository, effects *commitEffects) error {
+		granted, err := c.grantSkin(ctx, tx, toUserID, skinID, skinSourceGift, effects)
+		if err == nil && !granted {
+			err = ErrSkinAlreadyOwned
+		}
+		if err != nil {
+			return err
+		}
+		return tx.CreateSkinGift(ctx, gift)
+	})
+	if err != nil {
+		releaseLimit()
+		c.rollbackPayment(ctx, fromUserID, paymentID)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+		return fmt.Errorf("%s: failed to finalize payment: %w", op, err)
+	}
+	c.recordOperation(ctx, fromUserID, dto.FraudOperationSkinGift)
+
+	event := kafkaproducer.SkinGiftedEvent{
+		Type:       kafkaproducer.EventSkinGifted,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		SkinID:     skinID,
+		GiftedAt:   time.Now(),
+	}
+	if err := c.kafkaProducer.SendEvent(ctx, fmt.Sprint(toUserID), event); err != nil {
+		logger.Error("failed to send skin gifted event", "toUserID", toUserID, "error", err)
+	}
+
+	logger.Info("skin gifted", "fromUserID", fromUserID, "toUserID", toUserID, "skinID", skinID)
+	return nil
+}
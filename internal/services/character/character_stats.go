@@ -0,0 +1,166 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+// AllocateStats - spends free stat points on attributes, points are granted for every level above the first
+// Not exposed over gRPC yet: protos_chadnaldo v0.0.45 has no RPC for it.
+func (c *Character) AllocateStats(ctx context.Context, userID int64, stats dto.AttributesDTO) (*dto.AttributesDTO, error) {
+	const op = "services.character.AllocateStats"
+	logger := c.log.With("op", op)
+
+	if stats.Strength < 0 || stats.Luck < 0 || stats.Stamina < 0 || stats.Total() == 0 {
+		return nil, ErrInvalidStatAllocation
+	}
+
+	if _, err := c.characterProvider.GetCharacterLevel(storage.WithPrimary(ctx), userID); err != nil {
+		logger.Error("Error with getting character level", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	attributes, err := c.characterProvider.AllocateStats(ctx, userID, stats, c.cfg.Attributes.PointsPerLevel)
+	if err != nil {
+		logger.Error("Error with allocating stats", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if attributes == nil {
+		return nil, ErrNotEnoughStatPoints
+	}
+
+	c.invalidate(ctx, userID, cachekeys.MutationAttributes)
+
+	logger.Info("stats allocated", "userID", userID, "strength", stats.Strength, "luck", stats.Luck, "stamina", stats.Stamina)
+	return attributes, nil
+}
+
+// RespecStats - returns all allocated points back for configured price
+func (c *Character) RespecStats(ctx context.Context, userID int64) error {
+	const op = "services.character.RespecStats"
+	logger := c.log.With("op", op)
+
+	// Очки могли быть распределены только что, проверяем по основной базе
+	character, err := c.characterProvider.GetCharacter(storage.WithPrimary(ctx), userID)
+	if err != nil {
+		logger.Error("Error with getting character", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if character.AttributesDTO.Total() == 0 {
+		return ErrNothingToRespec
+	}
+
+	price := c.cfg.Attributes.RespecPrice
+	if price <= 0 {
+		if err := c.resetStats(ctx, userID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	paymentID := uuid.New().String()
+	if err := c.userClient.InitiatePayment(ctx, userID, price, paymentID); err != nil {
+		logger.Error("Error with initiating payment", "userID", userID, "error", err)
+		return fmt.Errorf("%s: failed to initiate payment: %w", op, err)
+	}
+
+	if err := c.resetStats(ctx, userID); err != nil {
+		c.rollbackPayment(ctx, userID, paymentID)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.userClient.FinalizePayment(ctx, paymentID, true); err != nil {
+		return fmt.Errorf("%s: failed to finalize payment: %w", op, err)
+	}
+
+	logger.Info("stats reset", "userID", userID)
+	return nil
+}
+
+// resetStats - returns allocated points back in the transaction, fails when there is nothing to reset
+func (c *Character) resetStats(ctx context.Context, userID int64) error {
+	_, err := c.inTx(ctx, userID, func(tx storage.TxRepository, effects *commitEffects) error {
+		reset, err := tx.ResetStats(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !reset {
+			// Очки успели сбросить параллельным запросом
+			return ErrNothingToRespec
+		}
+		effects.mutations = append(effects.mutations, cachekeys.MutationAttributes)
+		return nil
+	})
+	return err
+}
+
+// GetMiningStats - returns mining rate and duration of the character with all bonuses applied
+func (c *Character) GetMiningStats(ctx context.Context, userID int64) (*dto.MiningStatsDTO, error) {
+	const op = "services.character.GetMiningStats"
+
+	character, err := c.GetCharacter(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.MiningStatsDTO{
+		MiningRate:     character.MiningRate,
+		MiningDuration: character.MiningDuration,
+	}, nil
+}
+
+// deriveStats - returns copy of character with attributes, equipment, active boosts and collection bonuses applied,
+// base values stay in cache. The only place where derived stats are computed, everything is taken from
+// the cached snapshot and skins catalog.
+func (c *Character) deriveStats(ctx context.Context, aggregate *dto.CharacterAggregateDTO) *dto.GetCharacterDTO {
+	character := &aggregate.Character
+	cfg := c.cfg.Attributes
+	bonuses := attributeBonuses(character.AttributesDTO, cfg)
+
+	equipment := dto.EquipmentBonuses(aggregate.Equipment)
+	bonuses.MiningForcePercent += equipment.MiningForcePercent
+	bonuses.MiningDurationPercent += equipment.MiningDurationPercent
+	bonuses.GameMultiplierPercent += equipment.GameMultiplierPercent
+
+	boosts := dto.ActiveBoosts(aggregate.Boosts, time.Now())
+	bonuses.MiningForcePercent += dto.BoostsPercent(boosts, dto.BoostMiningForce)
+	bonuses.GameMultiplierPercent += dto.BoostsPercent(boosts, dto.BoostGameMultiplier)
+
+	// Бонусы коллекций не зависят от цен, каталог берем из кэша без персональных цен
+	skins, err := loadCached(ctx, c, cachekeys.AllSkinsInfo, c.cfg.Redis.Lifetime, c.characterProvider.GetAllSkins)
+	if err != nil {
+		c.log.Error("error with getting skins for collection bonuses", "userID", aggregate.UserID, "error", err)
+	} else {
+		skins.UpdateSkinsOpenStatus(character.CurrentLevel, aggregate.OwnedSkins)
+		skins.UpdateCollectionsStatus()
+		bonuses.MiningForcePercent += skins.CollectionBonusPercent(dto.BoostMiningForce)
+		bonuses.GameMultiplierPercent += skins.CollectionBonusPercent(dto.BoostGameMultiplier)
+	}
+
+	derived := character.ApplyBonuses(bonuses)
+	derived.FreeStatPoints = max(0, (character.CurrentLevel-1)*cfg.PointsPerLevel-character.AttributesDTO.Total())
+	return derived
+}
+
+// attributeBonuses - percent bonuses of allocated attributes according to configured formulas
+func attributeBonuses(attributes dto.AttributesDTO, cfg config.AttributesConfig) dto.CharacterBonusesDTO {
+	var bonuses dto.CharacterBonusesDTO
+
+	add := func(points int, formula config.AttributeFormula) {
+		bonuses.MiningForcePercent += points * formula.MiningForcePercent
+		bonuses.MiningDurationPercent += points * formula.MiningDurationPercent
+		bonuses.GameMultiplierPercent += points * formula.GameMultiplierPercent
+	}
+	add(attributes.Strength, cfg.Strength)
+	add(attributes.Luck, cfg.Luck)
+	add(attributes.Stamina, cfg.Stamina)
+
+	return bonuses
+}
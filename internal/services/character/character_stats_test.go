@@ -0,0 +1,128 @@
+package characterservice
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// skinsCatalogStub - character provider serving only the skins catalog
+type skinsCatalogStub struct {
+	storage.ICharacterProvider
+	skins *dto.GetSkinsDTO
+}
+
+func (s skinsCatalogStub) GetAllSkins(context.Context) (*dto.GetSkinsDTO, error) {
+	return s.skins, nil
+}
+
+var testAttributes = config.AttributesConfig{
+	PointsPerLevel: 2,
+	Strength:       config.AttributeFormula{MiningForcePercent: 2},
+	Luck:           config.AttributeFormula{GameMultiplierPercent: 3},
+	Stamina:        config.AttributeFormula{MiningDurationPercent: 5, MiningForcePercent: 1},
+}
+
+func TestAttributeBonuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes dto.AttributesDTO
+		want       dto.CharacterBonusesDTO
+	}{
+		{name: "no points", want: dto.CharacterBonusesDTO{}},
+		{
+			name:       "strength only",
+			attributes: dto.AttributesDTO{Strength: 3},
+			want:       dto.CharacterBonusesDTO{MiningForcePercent: 6},
+		},
+		{
+			name:       "formulas are summed",
+			attributes: dto.AttributesDTO{Strength: 1, Luck: 2, Stamina: 4},
+			want:       dto.CharacterBonusesDTO{MiningForcePercent: 6, MiningDurationPercent: 20, GameMultiplierPercent: 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attributeBonuses(tt.attributes, testAttributes); got != tt.want {
+				t.Errorf("attributeBonuses() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveStats(t *testing.T) {
+	ctx := context.Background()
+	memory := cache.NewMemoryCache(config.MemoryCacheConfig{Size: 10})
+	c := &Character{
+		log:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cfg:   &config.Config{Attributes: testAttributes},
+		cache: memory,
+		// Пустой каталог: бонусов коллекций нет
+		characterProvider: skinsCatalogStub{skins: &dto.GetSkinsDTO{}},
+	}
+
+	base := dto.GetCharacterDTO{CurrentLevel: 5, MiningRate: 100, MiningDuration: 60, GameMultiplier: 10}
+	withAttributes := base
+	withAttributes.AttributesDTO = dto.AttributesDTO{Strength: 5, Stamina: 2}
+
+	tests := []struct {
+		name      string
+		aggregate dto.CharacterAggregateDTO
+		want      dto.GetCharacterDTO
+	}{
+		{
+			name:      "base values",
+			aggregate: dto.CharacterAggregateDTO{Character: base},
+			want:      dto.GetCharacterDTO{CurrentLevel: 5, MiningRate: 100, MiningDuration: 60, GameMultiplier: 10, FreeStatPoints: 8},
+		},
+		{
+			name:      "attributes",
+			aggregate: dto.CharacterAggregateDTO{Character: withAttributes},
+			want: dto.GetCharacterDTO{
+				CurrentLevel: 5, MiningRate: 112, MiningDuration: 66, GameMultiplier: 10,
+				AttributesDTO: dto.AttributesDTO{Strength: 5, Stamina: 2}, FreeStatPoints: 1,
+			},
+		},
+		{
+			name: "equipped items and active boosts",
+			aggregate: dto.CharacterAggregateDTO{
+				Character: base,
+				Equipment: []dto.InventoryItemDTO{
+					{ItemDTO: dto.ItemDTO{MiningForcePercent: 10, MiningDurationPercent: 50}, IsEquipped: true},
+					{ItemDTO: dto.ItemDTO{MiningForcePercent: 100}},
+				},
+				Boosts: []dto.BoostDTO{
+					{Type: dto.BoostGameMultiplier, Percent: 20, ExpiresAt: time.Now().Add(time.Hour)},
+					{Type: dto.BoostMiningForce, Percent: 100, ExpiresAt: time.Now().Add(-time.Hour)},
+				},
+			},
+			want: dto.GetCharacterDTO{CurrentLevel: 5, MiningRate: 110, MiningDuration: 90, GameMultiplier: 12, FreeStatPoints: 8},
+		},
+		{
+			name:      "free points are not negative",
+			aggregate: dto.CharacterAggregateDTO{Character: dto.GetCharacterDTO{CurrentLevel: 1, AttributesDTO: dto.AttributesDTO{Luck: 3}}},
+			want:      dto.GetCharacterDTO{CurrentLevel: 1, AttributesDTO: dto.AttributesDTO{Luck: 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregate := tt.aggregate
+			got := c.deriveStats(ctx, &aggregate)
+			if *got != tt.want {
+				t.Errorf("deriveStats() = %+v, want %+v", *got, tt.want)
+			}
+			if aggregate.Character != tt.aggregate.Character {
+				t.Errorf("deriveStats() changed cached character: %+v", aggregate.Character)
+			}
+		})
+	}
+}
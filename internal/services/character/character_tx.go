@@ -0,0 +1,67 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+
+	kafkaproducer "github.com/Silverman143/character-service/internal/kafka/producer"
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	"github.com/Silverman143/character-service/internal/storage"
+)
+
+// commitEffects - changes made in a transaction that are applied to cache, leaderboards and kafka after it commits
+type commitEffects struct {
+	mutations []cachekeys.Mutation
+	levelUp   bool
+	soldOut   []kafkaproducer.SkinSoldOutEvent
+	// coins - rewarded coins, paid by user service after commit
+	coins int64
+	// stored - rewards were written to storage besides the claim itself, so the claim must not be removed
+	stored bool
+}
+
+// inTx - runs fn in one storage transaction and applies collected effects of userID after commit.
+// Fn is run again on serialization failures, so it must only write to tx and effects.
+func (c *Character) inTx(ctx context.Context, userID int64, fn func(tx storage.TxRepository, effects *commitEffects) error) (*commitEffects, error) {
+	var effects commitEffects
+	err := c.txProvider.WithTx(ctx, func(tx storage.TxRepository) error {
+		effects = commitEffects{}
+		return fn(tx, &effects)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.applyEffects(ctx, userID, &effects)
+	return &effects, nil
+}
+
+// applyEffects - updates cache, leaderboards and sends events of the committed transaction, errors are only logged
+func (c *Character) applyEffects(ctx context.Context, userID int64, effects *commitEffects) {
+	if len(effects.mutations) > 0 {
+		c.invalidate(ctx, userID, effects.mutations...)
+	}
+
+	if effects.levelUp {
+		if err := c.updateLevelLeaderboard(ctx, userID); err != nil {
+			c.log.Error("failed to update level leaderboard", "userID", userID, "error", err)
+		}
+	}
+
+	for _, event := range effects.soldOut {
+		if err := c.kafkaProducer.SendEvent(ctx, fmt.Sprint(event.SkinID), event); err != nil {
+			c.log.Error("failed to send skin sold out event", "skinID", event.SkinID, "error", err)
+		}
+	}
+}
+
+// payCoins - pays coins rewarded in the committed transaction
+func (c *Character) payCoins(ctx context.Context, userID int64, effects *commitEffects) error {
+	if effects.coins <= 0 {
+		return nil
+	}
+	if _, err := c.userClient.AddCoins(ctx, userID, effects.coins); err != nil {
+		return fmt.Errorf("failed to add coins: %w", err)
+	}
+	return nil
+}
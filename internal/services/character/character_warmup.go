@@ -0,0 +1,101 @@
+package characterservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/lib/cachekeys"
+	cache "github.com/Silverman143/character-service/internal/redis"
+	"golang.org/x/sync/errgroup"
+)
+
+// WarmupCatalog - loads skins catalog and level prices into cache, so first requests do not wait for Postgres
+func (c *Character) WarmupCatalog(ctx context.Context) error {
+	const op = "services.character.WarmupCatalog"
+
+	group, ctx := errgroup.WithContext(ctx)
+
+	group.Go(func() error {
+		_, err := loadCached(ctx, c, cachekeys.AllSkinsInfo, c.cfg.Redis.Lifetime, c.characterProvider.GetAllSkins)
+		return err
+	})
+
+	group.Go(func() error {
+		_, err := c.GetLevelsPrices(ctx)
+		return err
+	})
+
+	if err := group.Wait(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.log.Info("cache catalog warmed up", "op", op)
+	return nil
+}
+
+// PreloadRecentCharacters - loads most recently active characters into cache, returns loaded count.
+// Errors of single characters are only logged.
+func (c *Character) PreloadRecentCharacters(ctx context.Context) (int, error) {
+	const op = "services.character.PreloadRecentCharacters"
+	logger := c.log.With("op", op)
+
+	cfg := c.cfg.Warmup
+	if cfg.RecentCharacters <= 0 {
+		return 0, nil
+	}
+
+	// Размер мог уменьшиться в конфиге с прошлого запуска
+	if err := c.cache.ZTrim(ctx, cachekeys.RecentlyActive, cfg.RecentCharacters); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	recent, err := c.cache.ZRevRange(ctx, cachekeys.RecentlyActive, 0, cfg.RecentCharacters-1)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(max(cfg.Concurrency, 1))
+
+	loaded := make([]bool, len(recent))
+	for i, member := range recent {
+		group.Go(func() error {
+			if _, err := c.characterAggregate(ctx, member.Member); err != nil {
+				logger.Error("Error with preloading character", "userID", member.Member, "error", err)
+				return nil
+			}
+			loaded[i] = true
+			return nil
+		})
+	}
+	group.Wait()
+
+	count := 0
+	for _, ok := range loaded {
+		if ok {
+			count++
+		}
+	}
+
+	logger.Info("recent characters preloaded", "count", count)
+	return count, nil
+}
+
+// touchActivity - marks the user as recently active for preloading after restart, errors are only logged.
+// Set is trimmed on every write, so it keeps only as many users as are preloaded.
+func (c *Character) touchActivity(ctx context.Context, userID int64) {
+	keep := c.cfg.Warmup.RecentCharacters
+	if keep <= 0 {
+		return
+	}
+
+	member := cache.ZSetMember{Member: userID, Score: float64(time.Now().Unix())}
+	if err := c.cache.ZAdd(ctx, cachekeys.RecentlyActive, member); err != nil {
+		c.log.Error("failed to mark user as recently active", "userID", userID, "error", err)
+		return
+	}
+	if err := c.cache.ZTrim(ctx, cachekeys.RecentlyActive, keep); err != nil {
+		c.log.Error("failed to trim recently active users", "error", err)
+	}
+}
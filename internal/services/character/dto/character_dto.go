@@ -1,11 +1,74 @@
 package dto
 
+import "time"
 
 type GetCharacterDTO struct {
 	Name 			string		`json:"skin_name" db:"character_name"`
+	Nickname		string		`json:"nickname" db:"nickname"`
     CurrentLevel  	int    		`json:"current_level" db:"current_level"`
 	MiningRate		int64		`json:"mining_rate" db:"mining_force"`
 	MiningDuration	int			`json:"mining_duration" db:"mining_duration_minutes"`
+	GameMultiplier	int			`json:"game_multiplier" db:"game_multiplayer"`
     SkinID         	int		 	`json:"current_skin_id" db:"skin_id"`
     SkinImgURL      string 		`json:"skin_image_url" db:"character_image_url"`
+	AttributesDTO
+	FreeStatPoints	int			`json:"free_stat_points" db:"-"`
+}
+
+// CharacterAggregateDTO - consistent snapshot of the character cached as a whole, character reads are derived from it.
+// Version grows with every change of the snapshot columns of the character row, owned skins, equipment or boosts.
+type CharacterAggregateDTO struct {
+	UserID			int64				`json:"user_id"`
+	Version			int64				`json:"version"`
+	Character		GetCharacterDTO		`json:"character"`
+	OwnedSkins		[]int				`json:"owned_skins"`
+	Equipment		[]InventoryItemDTO	`json:"equipment"`
+	// Boosts - boosts active at the moment of the snapshot, expired ones are skipped on read
+	Boosts			[]BoostDTO			`json:"boosts"`
+}
+
+// AttributesDTO - points allocated to character attributes
+type AttributesDTO struct {
+	Strength		int			`json:"strength" db:"strength"`
+	Luck			int			`json:"luck" db:"luck"`
+	Stamina			int			`json:"stamina" db:"stamina"`
+}
+
+// Total returns number of allocated points
+func (a AttributesDTO) Total() int {
+	return a.Strength + a.Luck + a.Stamina
+}
+
+// CharacterBonusesDTO - summary percent bonuses from boosts, completed skin collections and attributes
+type CharacterBonusesDTO struct {
+	MiningForcePercent		int		`json:"mining_force_percent"`
+	MiningDurationPercent	int		`json:"mining_duration_percent"`
+	GameMultiplierPercent	int		`json:"game_multiplier_percent"`
+}
+
+// ApplyBonuses returns copy of character with percent bonuses applied to base values
+func (c GetCharacterDTO) ApplyBonuses(bonuses CharacterBonusesDTO) *GetCharacterDTO {
+	c.MiningRate += c.MiningRate * int64(bonuses.MiningForcePercent) / 100
+	c.MiningDuration += c.MiningDuration * bonuses.MiningDurationPercent / 100
+	c.GameMultiplier += c.GameMultiplier * bonuses.GameMultiplierPercent / 100
+	return &c
+}
+
+// MiningStatsDTO - mining values of the character with all bonuses applied
+type MiningStatsDTO struct {
+	MiningRate		int64		`json:"mining_rate"`
+	MiningDuration	int			`json:"mining_duration"`
+}
+
+// NicknameDTO - nickname of the character and time of its last change, both empty before the first rename
+type NicknameDTO struct {
+	Nickname		*string		`json:"nickname" db:"nickname"`
+	ChangedAt		*time.Time	`json:"changed_at" db:"nickname_changed_at"`
+}
+
+// RenameResultDTO - result of character rename
+type RenameResultDTO struct {
+	Nickname			string		`json:"nickname"`
+	Paid				bool		`json:"paid"`
+	NextFreeRenameAt	time.Time	`json:"next_free_rename_at"`
 }
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+const (
+	FraudOperationLevelUp       = "level_up"
+	FraudOperationSkinPurchase  = "skin_purchase"
+	FraudOperationSkinGift      = "skin_gift"
+	FraudOperationSeasonPremium = "season_premium"
+)
+
+const (
+	FraudSignalVelocity     = "velocity"
+	FraudSignalReferralJump = "referral_jump"
+	FraudSignalRollbacks    = "payment_rollbacks"
+)
+
+const (
+	FraudDecisionAllow  = "allow"
+	FraudDecisionReview = "review"
+	FraudDecisionBlock  = "block"
+)
+
+// FraudAssessmentDTO - fraud score of the operation with triggered signals
+type FraudAssessmentDTO struct {
+	Operation string   `json:"operation"`
+	Score     int      `json:"score"`
+	Signals   []string `json:"signals"`
+	Decision  string   `json:"decision"`
+}
+
+// FraudFlagDTO - character flagged for manual review
+type FraudFlagDTO struct {
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Score      int       `json:"score" db:"score"`
+	Reasons    string    `json:"reasons" db:"reasons"`
+	Operation  string    `json:"operation" db:"operation"`
+	FlagsCount int       `json:"flags_count" db:"flags_count"`
+	FlaggedAt  time.Time `json:"flagged_at" db:"flagged_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
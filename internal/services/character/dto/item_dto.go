@@ -0,0 +1,37 @@
+package dto
+
+// ItemDTO - item of the catalog with bonuses it gives while equipped
+type ItemDTO struct {
+	ID                    int    `json:"item_id" db:"item_id"`
+	Code                  string `json:"code" db:"code"`
+	Name                  string `json:"name" db:"name"`
+	Description           string `json:"description" db:"description"`
+	Type                  string `json:"item_type" db:"item_type"`
+	Slot                  string `json:"slot" db:"slot"`
+	ImageURL              string `json:"image_url" db:"image_url"`
+	MiningForcePercent    int    `json:"mining_force_percent" db:"mining_force_percent"`
+	MiningDurationPercent int    `json:"mining_duration_percent" db:"mining_duration_percent"`
+	GameMultiplierPercent int    `json:"game_multiplier_percent" db:"game_multiplier_percent"`
+	IsActive              bool   `json:"is_active" db:"is_active"`
+}
+
+// InventoryItemDTO - item in the user inventory
+type InventoryItemDTO struct {
+	ItemDTO
+	Quantity   int  `json:"quantity" db:"quantity"`
+	IsEquipped bool `json:"is_equipped" db:"is_equipped"`
+}
+
+// EquipmentBonuses returns summary bonuses of equipped items
+func EquipmentBonuses(inventory []InventoryItemDTO) CharacterBonusesDTO {
+	var bonuses CharacterBonusesDTO
+	for _, item := range inventory {
+		if !item.IsEquipped {
+			continue
+		}
+		bonuses.MiningForcePercent += item.MiningForcePercent
+		bonuses.MiningDurationPercent += item.MiningDurationPercent
+		bonuses.GameMultiplierPercent += item.GameMultiplierPercent
+	}
+	return bonuses
+}
@@ -0,0 +1,30 @@
+package dto
+
+// prestigeScoreBase - every prestige outweighs any regular level in the level board
+const prestigeScoreBase = 1000
+
+// CharacterRankingDTO - character values that leaderboards are built from
+type CharacterRankingDTO struct {
+	UserID     int64 `json:"user_id" db:"user_id"`
+	Level      int   `json:"current_level" db:"current_level"`
+	Prestige   int   `json:"prestige" db:"prestige"`
+	MinedCoins int64 `json:"total_mined_coins" db:"total_mined_coins"`
+}
+
+// LevelScore returns score of the character in the level leaderboard
+func (r CharacterRankingDTO) LevelScore() float64 {
+	return float64(r.Prestige*prestigeScoreBase + r.Level)
+}
+
+// LeaderboardEntryDTO - single position in a leaderboard
+type LeaderboardEntryDTO struct {
+	UserID int64 `json:"user_id"`
+	Rank   int64 `json:"rank"` // 1-based, 0 when user is not ranked
+	Score  int64 `json:"score"`
+}
+
+// UserRankDTO - user positions in all leaderboards
+type UserRankDTO struct {
+	Level  LeaderboardEntryDTO `json:"level"`
+	Mining LeaderboardEntryDTO `json:"mining"`
+}
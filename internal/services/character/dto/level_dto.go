@@ -1,5 +1,19 @@
 package dto
 
+const (
+    PaymentMethodAuto       = "auto"      // монеты, если хватает, иначе бесплатное открытие по рефералам
+    PaymentMethodCoins      = "coins"
+    PaymentMethodReferrals  = "referrals" // списание реферальных кредитов
+    PaymentMethodFree       = "free"      // бесплатное открытие по порогу рефералов
+)
+
+// LevelUpResultDTO - result of level up with applied payment method
+type LevelUpResultDTO struct {
+    NewLevel        int     `json:"new_level"`
+    CoinsBalance    int64   `json:"coins_balance"`
+    PaymentMethod   string  `json:"payment_method"`
+}
+
 // SkinPrice представляет скин и его цены
 type LevelPriceDTO struct {
     Level                 int     `json:"level_number" db:"level_number"`
@@ -21,4 +35,14 @@ func (spl *LevelPriceListDTO) GetLevelPrice(level int) (LevelPriceDTO, bool) {
         }
     }
     return LevelPriceDTO{}, false
-}
\ No newline at end of file
+}
+// MaxLevel - returns the highest level that has a price, levels above it can't be reached
+func (spl *LevelPriceListDTO) MaxLevel() int {
+    maxLevel := 0
+    for _, skin := range spl.Skins {
+        if skin.Level > maxLevel {
+            maxLevel = skin.Level
+        }
+    }
+    return maxLevel
+}
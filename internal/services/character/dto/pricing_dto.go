@@ -0,0 +1,118 @@
+package dto
+
+import "time"
+
+const (
+	PriceTargetLevelUp = "level_up"
+	PriceTargetSkin    = "skin"
+)
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// PriceCampaignDTO - discount for a segment of players during the time window
+type PriceCampaignDTO struct {
+	ID            int       `json:"campaign_id" db:"campaign_id"`
+	Name          string    `json:"name" db:"name"`
+	Target        string    `json:"target" db:"target"`
+	TargetID      *int      `json:"target_id,omitempty" db:"target_id"`
+	DiscountType  string    `json:"discount_type" db:"discount_type"`
+	DiscountValue int64     `json:"discount_value" db:"discount_value"`
+	MinLevel      *int      `json:"min_level,omitempty" db:"min_level"`
+	MaxLevel      *int      `json:"max_level,omitempty" db:"max_level"`
+	MinPrestige   *int      `json:"min_prestige,omitempty" db:"min_prestige"`
+	MaxPrestige   *int      `json:"max_prestige,omitempty" db:"max_prestige"`
+	StartsAt      time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt        time.Time `json:"ends_at" db:"ends_at"`
+	IsActive      bool      `json:"is_active" db:"is_active"`
+}
+
+// IsRunning reports whether campaign is active at now
+func (c PriceCampaignDTO) IsRunning(now time.Time) bool {
+	return c.IsActive && !now.Before(c.StartsAt) && now.Before(c.EndsAt)
+}
+
+// Matches reports whether campaign applies to the target and player segment
+func (c PriceCampaignDTO) Matches(target string, targetID int, segment PriceSegmentDTO) bool {
+	if c.Target != target || (c.TargetID != nil && *c.TargetID != targetID) {
+		return false
+	}
+	return inRange(segment.Level, c.MinLevel, c.MaxLevel) && inRange(segment.Prestige, c.MinPrestige, c.MaxPrestige)
+}
+
+// Apply returns discounted price, never below zero
+func (c PriceCampaignDTO) Apply(price int64) int64 {
+	switch c.DiscountType {
+	case DiscountPercent:
+		price -= price * c.DiscountValue / 100
+	case DiscountFixed:
+		price -= c.DiscountValue
+	}
+	return max(price, 0)
+}
+
+func inRange(value int, from, to *int) bool {
+	return (from == nil || value >= *from) && (to == nil || value <= *to)
+}
+
+// PriceSegmentDTO - player values campaigns are targeted by
+type PriceSegmentDTO struct {
+	Level    int
+	Prestige int
+}
+
+// PriceDTO - original and final price with the campaign that gave the discount
+type PriceDTO struct {
+	OriginalPrice int64 `json:"original_price"`
+	Price         int64 `json:"price"`
+	CampaignID    *int  `json:"campaign_id,omitempty"`
+}
+
+// BestPrice returns the lowest price among running campaigns matching the target, campaigns do not stack
+func BestPrice(campaigns []PriceCampaignDTO, now time.Time, target string, targetID int, segment PriceSegmentDTO, price int64) PriceDTO {
+	best := PriceDTO{OriginalPrice: price, Price: price}
+	for _, campaign := range campaigns {
+		if !campaign.IsRunning(now) || !campaign.Matches(target, targetID, segment) {
+			continue
+		}
+		if discounted := campaign.Apply(price); discounted < best.Price {
+			id := campaign.ID
+			best.Price = discounted
+			best.CampaignID = &id
+		}
+	}
+	return best
+}
+
+// HasRunning reports whether any campaign for the target is running at now
+func HasRunning(campaigns []PriceCampaignDTO, now time.Time, target string) bool {
+	for _, campaign := range campaigns {
+		if campaign.Target == target && campaign.IsRunning(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextBoundary returns the nearest start or end of campaigns after now, zero time if there is none
+func NextBoundary(campaigns []PriceCampaignDTO, now time.Time) time.Time {
+	var next time.Time
+	for _, campaign := range campaigns {
+		for _, boundary := range []time.Time{campaign.StartsAt, campaign.EndsAt} {
+			if boundary.After(now) && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+	return next
+}
+
+// LevelUpQuoteDTO - price of the next level for the user
+type LevelUpQuoteDTO struct {
+	Level int `json:"level"`
+	PriceDTO
+	ReferralsPrice       int64 `json:"referrals"`
+	ReferralsForFreeOpen int64 `json:"referral_to_open"`
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestBestPrice(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	running := func(id int, discountType string, value int64) PriceCampaignDTO {
+		return PriceCampaignDTO{
+			ID:            id,
+			Target:        PriceTargetSkin,
+			DiscountType:  discountType,
+			DiscountValue: value,
+			StartsAt:      now.Add(-time.Hour),
+			EndsAt:        now.Add(time.Hour),
+			IsActive:      true,
+		}
+	}
+	segment := PriceSegmentDTO{Level: 10, Prestige: 1}
+
+	inactive := running(1, DiscountPercent, 50)
+	inactive.IsActive = false
+	ended := running(2, DiscountPercent, 50)
+	ended.EndsAt = now
+	notStarted := running(3, DiscountPercent, 50)
+	notStarted.StartsAt = now.Add(time.Minute)
+	otherSkin := running(4, DiscountPercent, 50)
+	otherSkin.TargetID = intPtr(7)
+	thisSkin := running(5, DiscountPercent, 30)
+	thisSkin.TargetID = intPtr(3)
+	highLevels := running(6, DiscountPercent, 50)
+	highLevels.MinLevel = intPtr(11)
+	prestigeMatch := running(7, DiscountFixed, 40)
+	prestigeMatch.MinPrestige = intPtr(1)
+	prestigeMatch.MaxPrestige = intPtr(1)
+	levelUp := running(8, DiscountPercent, 90)
+	levelUp.Target = PriceTargetLevelUp
+
+	tests := []struct {
+		name      string
+		campaigns []PriceCampaignDTO
+		want      int64
+		wantID    *int
+	}{
+		{name: "no campaigns", want: 100},
+		{name: "percent", campaigns: []PriceCampaignDTO{running(1, DiscountPercent, 25)}, want: 75, wantID: intPtr(1)},
+		{name: "fixed", campaigns: []PriceCampaignDTO{running(1, DiscountFixed, 30)}, want: 70, wantID: intPtr(1)},
+		{name: "not below zero", campaigns: []PriceCampaignDTO{running(1, DiscountFixed, 300)}, want: 0, wantID: intPtr(1)},
+		{name: "lowest wins, no stacking", campaigns: []PriceCampaignDTO{running(1, DiscountPercent, 10), running(2, DiscountFixed, 20), running(3, DiscountPercent, 15)}, want: 80, wantID: intPtr(2)},
+		{name: "equal discount keeps first", campaigns: []PriceCampaignDTO{running(1, DiscountPercent, 20), running(2, DiscountFixed, 20)}, want: 80, wantID: intPtr(1)},
+		{name: "inactive, ended and not started are skipped", campaigns: []PriceCampaignDTO{inactive, ended, notStarted}, want: 100},
+		{name: "target id", campaigns: []PriceCampaignDTO{otherSkin, thisSkin}, want: 70, wantID: intPtr(5)},
+		{name: "segment", campaigns: []PriceCampaignDTO{highLevels, prestigeMatch}, want: 60, wantID: intPtr(7)},
+		{name: "other target", campaigns: []PriceCampaignDTO{levelUp}, want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BestPrice(tt.campaigns, now, PriceTargetSkin, 3, segment, 100)
+			if got.OriginalPrice != 100 || got.Price != tt.want {
+				t.Errorf("BestPrice() = %d (original %d), want %d", got.Price, got.OriginalPrice, tt.want)
+			}
+			switch {
+			case tt.wantID == nil && got.CampaignID != nil:
+				t.Errorf("BestPrice() campaign = %d, want none", *got.CampaignID)
+			case tt.wantID != nil && (got.CampaignID == nil || *got.CampaignID != *tt.wantID):
+				t.Errorf("BestPrice() campaign = %v, want %d", got.CampaignID, *tt.wantID)
+			}
+		})
+	}
+}
+
+func TestNextBoundary(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	campaign := func(starts, ends time.Duration) PriceCampaignDTO {
+		return PriceCampaignDTO{StartsAt: now.Add(starts), EndsAt: now.Add(ends)}
+	}
+
+	tests := []struct {
+		name      string
+		campaigns []PriceCampaignDTO
+		want      time.Time
+	}{
+		{name: "no campaigns"},
+		{name: "all boundaries passed", campaigns: []PriceCampaignDTO{campaign(-2*time.Hour, -time.Hour)}},
+		{name: "boundary at now is passed", campaigns: []PriceCampaignDTO{campaign(-time.Hour, 0)}},
+		{name: "end of running campaign", campaigns: []PriceCampaignDTO{campaign(-time.Hour, 3*time.Hour)}, want: now.Add(3 * time.Hour)},
+		{name: "start of future campaign", campaigns: []PriceCampaignDTO{campaign(2*time.Hour, 5*time.Hour)}, want: now.Add(2 * time.Hour)},
+		{
+			name:      "nearest of all",
+			campaigns: []PriceCampaignDTO{campaign(-time.Hour, 3*time.Hour), campaign(2*time.Hour, 5*time.Hour), campaign(-time.Hour, 90*time.Minute)},
+			want:      now.Add(90 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextBoundary(tt.campaigns, now); !got.Equal(tt.want) {
+				t.Errorf("NextBoundary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
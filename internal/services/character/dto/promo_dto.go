@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// PromoCodeDTO - promo code with its limits and reward bundle
+type PromoCodeDTO struct {
+	ID               int         `json:"promo_code_id" db:"promo_code_id"`
+	Code             string      `json:"code" db:"code"`
+	BatchID          string      `json:"batch_id" db:"batch_id"`
+	MaxRedemptions   *int        `json:"max_redemptions,omitempty" db:"max_redemptions"`
+	RedemptionsCount int         `json:"redemptions_count" db:"redemptions_count"`
+	PerUserLimit     int         `json:"per_user_limit" db:"per_user_limit"`
+	MinLevel         int         `json:"min_level" db:"min_level"`
+	ExpiresAt        *time.Time  `json:"expires_at,omitempty" db:"expires_at"`
+	IsActive         bool        `json:"is_active" db:"is_active"`
+	Rewards          []RewardDTO `json:"rewards" db:"-"`
+}
+
+// IsExpired reports whether promo code can not be redeemed at now anymore
+func (p PromoCodeDTO) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && !now.Before(*p.ExpiresAt)
+}
+
+// PromoCodeBatchDTO - parameters of generated promo codes, every code of the batch gets the same limits and rewards
+type PromoCodeBatchDTO struct {
+	BatchID        string      `json:"batch_id"`
+	Prefix         string      `json:"prefix"`
+	Count          int         `json:"count"`
+	MaxRedemptions *int        `json:"max_redemptions,omitempty"`
+	PerUserLimit   int         `json:"per_user_limit"`
+	MinLevel       int         `json:"min_level"`
+	ExpiresAt      *time.Time  `json:"expires_at,omitempty"`
+	Rewards        []RewardDTO `json:"rewards"`
+}
+
+// PromoRedemptionDTO - result of promo code redemption
+type PromoRedemptionDTO struct {
+	Code    string      `json:"code"`
+	Rewards []RewardDTO `json:"rewards"`
+}
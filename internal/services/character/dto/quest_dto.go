@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+const (
+	QuestPeriodDaily  = "daily"
+	QuestPeriodWeekly = "weekly"
+)
+
+const (
+	QuestActionMiningClaimed = "mining_claimed"
+	QuestActionGameFinished  = "game_finished"
+	QuestActionSkinChanged   = "skin_changed"
+	QuestActionLevelUp       = "level_up"
+	QuestActionDailyClaimed  = "daily_claimed"
+)
+
+// QuestTemplateDTO - quest description assigned to every character each period
+type QuestTemplateDTO struct {
+	ID          int    `json:"template_id" db:"template_id"`
+	Code        string `json:"code" db:"code"`
+	Title       string `json:"title" db:"title"`
+	Description string `json:"description" db:"description"`
+	Period      string `json:"period" db:"period"`
+	Action      string `json:"action" db:"action"`
+	Target      int    `json:"target" db:"target"`
+	IsActive    bool   `json:"is_active" db:"is_active"`
+	RewardDTO
+}
+
+// QuestDTO - quest assigned to the character for the current period
+type QuestDTO struct {
+	ID          int64      `json:"quest_id" db:"quest_id"`
+	TemplateID  int        `json:"template_id" db:"template_id"`
+	Code        string     `json:"code" db:"code"`
+	Title       string     `json:"title" db:"title"`
+	Description string     `json:"description" db:"description"`
+	Period      string     `json:"period" db:"period"`
+	Action      string     `json:"action" db:"action"`
+	Progress    int        `json:"progress" db:"progress"`
+	Target      int        `json:"target" db:"target"`
+	PeriodStart time.Time  `json:"period_start" db:"period_start"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty" db:"claimed_at"`
+	RewardDTO
+}
+
+// IsQuestAction reports whether action can be used in quest templates
+func IsQuestAction(action string) bool {
+	switch action {
+	case QuestActionMiningClaimed, QuestActionGameFinished, QuestActionSkinChanged, QuestActionLevelUp, QuestActionDailyClaimed:
+		return true
+	}
+	return false
+}
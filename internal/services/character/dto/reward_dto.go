@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+)
+
+const (
+	RewardCoins = "coins"
+	RewardBoost = "boost"
+	RewardSkin  = "skin"
+	RewardLevel = "level"
+)
+
+const (
+	BoostMiningForce    = "mining_force"
+	BoostGameMultiplier = "game_multiplier"
+)
+
+// RewardDTO - reward granted to the user. Amount is coins amount, boost percent or levels count depending on type
+type RewardDTO struct {
+	Type            string `json:"reward_type" db:"reward_type"`
+	Amount          int64  `json:"amount" db:"amount"`
+	SkinID          *int   `json:"skin_id,omitempty" db:"skin_id"`
+	BoostType       string `json:"boost_type,omitempty" db:"boost_type"`
+	DurationMinutes int    `json:"duration_minutes,omitempty" db:"duration_minutes"`
+}
+
+// NewRewardDTO converts reward from config
+func NewRewardDTO(cfg config.RewardConfig) RewardDTO {
+	return RewardDTO{
+		Type:            cfg.Type,
+		Amount:          cfg.Amount,
+		SkinID:          cfg.SkinID,
+		BoostType:       cfg.BoostType,
+		DurationMinutes: cfg.DurationMinutes,
+	}
+}
+
+// DailyClaimDTO - claimed day of the daily rewards calendar
+type DailyClaimDTO struct {
+	ClaimDate time.Time `json:"claim_date" db:"claim_date"`
+	Streak    int       `json:"streak" db:"streak"`
+}
+
+// DailyRewardDTO - result of daily reward claim
+type DailyRewardDTO struct {
+	DailyClaimDTO
+	Reward RewardDTO `json:"reward"`
+}
+
+// BoostDTO - active temporary boost of the character
+type BoostDTO struct {
+	Type      string    `json:"boost_type" db:"boost_type"`
+	Percent   int       `json:"percent" db:"percent"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// ActiveBoosts returns boosts not expired at the moment
+func ActiveBoosts(boosts []BoostDTO, now time.Time) []BoostDTO {
+	active := make([]BoostDTO, 0, len(boosts))
+	for _, b := range boosts {
+		if b.ExpiresAt.After(now) {
+			active = append(active, b)
+		}
+	}
+	return active
+}
+
+// BoostsPercent returns summary percent of active boosts of given type
+func BoostsPercent(boosts []BoostDTO, boostType string) int {
+	var percent int
+	for _, b := range boosts {
+		if b.Type == boostType {
+			percent += b.Percent
+		}
+	}
+	return percent
+}
@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+const (
+	SeasonScheduled = "scheduled"
+	SeasonActive    = "active"
+	SeasonFinished  = "finished"
+)
+
+// SeasonDTO - season with its tiers
+type SeasonDTO struct {
+	ID           int             `json:"season_id" db:"season_id"`
+	Name         string          `json:"name" db:"name"`
+	StartsAt     time.Time       `json:"starts_at" db:"starts_at"`
+	EndsAt       time.Time       `json:"ends_at" db:"ends_at"`
+	PremiumPrice int64           `json:"premium_price" db:"premium_price"`
+	// PremiumFree - premium track is given for free, zero price without it means the price is not set
+	PremiumFree  bool            `json:"premium_free" db:"premium_free"`
+	Status       string          `json:"status" db:"status"`
+	Tiers        []SeasonTierDTO `json:"tiers"`
+}
+
+// SeasonTierDTO - season tier with rewards of both tracks
+type SeasonTierDTO struct {
+	Number        int        `json:"tier_number" db:"tier_number"`
+	XPRequired    int64      `json:"xp_required" db:"xp_required"`
+	FreeReward    *RewardDTO `json:"free_reward,omitempty"`
+	PremiumReward *RewardDTO `json:"premium_reward,omitempty"`
+}
+
+// SeasonTierRewardDTO - reward row of the season tier
+type SeasonTierRewardDTO struct {
+	TierNumber int  `db:"tier_number"`
+	IsPremium  bool `db:"is_premium"`
+	RewardDTO
+}
+
+// SeasonProgressDTO - user progress in the season
+type SeasonProgressDTO struct {
+	UserID    int64 `json:"user_id" db:"user_id"`
+	SeasonID  int   `json:"season_id" db:"season_id"`
+	XP        int64 `json:"xp" db:"xp"`
+	IsPremium bool  `json:"is_premium" db:"is_premium"`
+}
+
+// SeasonTierClaimDTO - already claimed season tier reward
+type SeasonTierClaimDTO struct {
+	TierNumber int  `db:"tier_number"`
+	IsPremium  bool `db:"is_premium"`
+}
+
+// SeasonProgressInfoDTO - season progress with claim status of every tier
+type SeasonProgressInfoDTO struct {
+	Season      SeasonDTO             `json:"season"`
+	XP          int64                 `json:"xp"`
+	IsPremium   bool                  `json:"is_premium"`
+	CurrentTier int                   `json:"current_tier"`
+	Tiers       []SeasonTierStatusDTO `json:"tiers"`
+}
+
+// SeasonTierStatusDTO - tier of the season with user claim status
+type SeasonTierStatusDTO struct {
+	SeasonTierDTO
+	Reached        bool `json:"reached"`
+	FreeClaimed    bool `json:"free_claimed"`
+	PremiumClaimed bool `json:"premium_claimed"`
+}
+
+// GetTier returns tier by its number
+func (s *SeasonDTO) GetTier(number int) (SeasonTierDTO, bool) {
+	for _, tier := range s.Tiers {
+		if tier.Number == number {
+			return tier, true
+		}
+	}
+	return SeasonTierDTO{}, false
+}
+
+// CurrentTier returns highest tier reached with given xp
+func (s *SeasonDTO) CurrentTier(xp int64) int {
+	var current int
+	for _, tier := range s.Tiers {
+		if xp >= tier.XPRequired && tier.Number > current {
+			current = tier.Number
+		}
+	}
+	return current
+}
@@ -1,9 +1,31 @@
 package dto
 
-import characterv1 "github.com/Silverman143/protos_chadnaldo/gen/go/character"
+import (
+	"time"
+
+	characterv1 "github.com/Silverman143/protos_chadnaldo/gen/go/character"
+)
+
+const (
+	RarityCommon    = "common"
+	RarityUncommon  = "uncommon"
+	RarityRare      = "rare"
+	RarityEpic      = "epic"
+	RarityLegendary = "legendary"
+)
 
 type GetSkinsDTO struct {
-	Skins []SkinInfoDTO
+	Skins []SkinInfoDTO				`json:"skins"`
+	Collections []SkinCollectionDTO	`json:"collections"`
+}
+
+// SkinCollectionDTO - set of skins, owning all of them grants permanent bonus
+type SkinCollectionDTO struct {
+	ID				int			`json:"collection_id" db:"collection_id"`
+	Name			string		`json:"name" db:"name"`
+	BonusType		string		`json:"bonus_type" db:"bonus_type"`
+	BonusPercent	int			`json:"bonus_percent" db:"bonus_percent"`
+	IsCompleted		bool		`json:"is_completed"`
 }
 
 type SkinInfoDTO struct {
@@ -13,18 +35,111 @@ type SkinInfoDTO struct {
 	ImageURL 		string		`json:"character_image_url" db:"character_image_url"`
 	UnlockLevel		int			`json:"unlock_level" db:"unlock_level"`
 	Price			int64		`json:"price" db:"price"`
+	OriginalPrice	int64		`json:"original_price"`
+	CampaignID		*int		`json:"campaign_id,omitempty"`
 	RefToBuy 		int			`json:"referrals" db:"referrals"`
 	RefToOpen   	int			`json:"referral_to_open" db:"referral_to_open"`
+	IsExclusive		bool		`json:"is_exclusive" db:"is_exclusive"`
+	Rarity			string		`json:"rarity" db:"rarity"`
+	CollectionID	*int		`json:"collection_id,omitempty" db:"collection_id"`
+	AvailableFrom	*time.Time	`json:"available_from,omitempty" db:"available_from"`
+	AvailableUntil	*time.Time	`json:"available_until,omitempty" db:"available_until"`
+	MaxSupply		*int		`json:"max_supply,omitempty" db:"max_supply"`
+	SoldCount		int			`json:"sold_count" db:"sold_count"`
 	IsOpened		bool		`json:"is_opened"`
-	Stats 			SkinStats   
+	IsAvailable		bool		`json:"is_available"`
+	RemainingSupply	*int		`json:"remaining_supply,omitempty"`
+	Stats 			SkinStats	`json:"stats"`
+}
+
+// IsLimited - skin has time window or limited supply
+func (s *SkinInfoDTO) IsLimited() bool {
+    return s.AvailableFrom != nil || s.AvailableUntil != nil || s.MaxSupply != nil
+}
+
+// UpdateSkinsAvailability - annotates skins with availability and remaining supply at the given moment
+func (s *GetSkinsDTO) UpdateSkinsAvailability(now time.Time) {
+    for i := range s.Skins {
+        skin := &s.Skins[i]
+        skin.IsAvailable = true
+
+        if skin.AvailableFrom != nil && now.Before(*skin.AvailableFrom) {
+            skin.IsAvailable = false
+        }
+        if skin.AvailableUntil != nil && !now.Before(*skin.AvailableUntil) {
+            skin.IsAvailable = false
+        }
+        if skin.MaxSupply != nil {
+            remaining := *skin.MaxSupply - skin.SoldCount
+            if remaining < 0 {
+                remaining = 0
+            }
+            skin.RemainingSupply = &remaining
+            if remaining == 0 {
+                skin.IsAvailable = false
+            }
+        }
+    }
+}
+
+// GetSkin returns skin by id
+func (s *GetSkinsDTO) GetSkin(skinID int) (SkinInfoDTO, bool) {
+    for _, skin := range s.Skins {
+        if skin.ID == skinID {
+            return skin, true
+        }
+    }
+    return SkinInfoDTO{}, false
 }
 
-func(s *GetSkinsDTO) UpdateSkinsOpenStatus(currentLevel int) {
+// UpdateSkinsOpenStatus - skin is opened when it is granted to user or, for non exclusive skins, unlocked by level
+func(s *GetSkinsDTO) UpdateSkinsOpenStatus(currentLevel int, ownedSkins []int) {
+    owned := make(map[int]struct{}, len(ownedSkins))
+    for _, id := range ownedSkins {
+        owned[id] = struct{}{}
+    }
+
     for i := range s.Skins {
-        if currentLevel >= s.Skins[i].UnlockLevel {
+        if _, ok := owned[s.Skins[i].ID]; ok {
             s.Skins[i].IsOpened = true
+            continue
+        }
+        if !s.Skins[i].IsExclusive && currentLevel >= s.Skins[i].UnlockLevel {
+            s.Skins[i].IsOpened = true
+        }
+    }
+}
+
+// UpdateCollectionsStatus - collection is completed when every its skin is opened
+func (s *GetSkinsDTO) UpdateCollectionsStatus() {
+    for i := range s.Collections {
+        collection := &s.Collections[i]
+        collection.IsCompleted = false
+
+        var total int
+        completed := true
+        for _, skin := range s.Skins {
+            if skin.CollectionID == nil || *skin.CollectionID != collection.ID {
+                continue
+            }
+            total++
+            if !skin.IsOpened {
+                completed = false
+            }
+        }
+        collection.IsCompleted = completed && total > 0
+    }
+}
+
+// CollectionBonusPercent returns summary bonus percent of completed collections of given type
+func (s *GetSkinsDTO) CollectionBonusPercent(bonusType string) int {
+    var percent int
+    for _, collection := range s.Collections {
+        if collection.IsCompleted && collection.BonusType == bonusType {
+            percent += collection.BonusPercent
         }
     }
+    return percent
 }
 
 func (s *GetSkinsDTO) IsOpened(skinID int) bool {
@@ -52,6 +167,7 @@ func (s *GetSkinsDTO) ToGetAllSkinsResponse() *characterv1.GetAllSkinsResponse {
             ReferralsToBuy:  int32(skin.RefToBuy),
             ReferralsToOpen: int32(skin.RefToOpen),
             Bought:          skin.IsOpened, // Предполагаем, что IsOpened соответствует bought
+            // BLOCKED: Rarity, CollectionID и OriginalPrice не передаются, пока в SkinInfo (protos_chadnaldo v0.0.45) нет таких полей
             Stats: &characterv1.SkinStats{
                 GamesPlayed: int32(skin.Stats.GamesPlayed),
                 HoursPlayed: int32(skin.Stats.HoursPlayed),
@@ -63,10 +179,26 @@ func (s *GetSkinsDTO) ToGetAllSkinsResponse() *characterv1.GetAllSkinsResponse {
     return response
 }
 
+// SkinGrantDTO - result of giving skin to the user
+type SkinGrantDTO struct {
+	Granted   bool // false if user already owned the skin
+	SoldOut   bool // this grant took the last copy of limited skin
+	MaxSupply *int
+}
+
+// SkinGiftDTO - skin bought by one user for another
+type SkinGiftDTO struct {
+	FromUserID	int64	`db:"from_user_id"`
+	ToUserID	int64	`db:"to_user_id"`
+	SkinID		int		`db:"skin_id"`
+	Price		int64	`db:"price"`
+	PaymentID	string	`db:"payment_id"`
+}
+
 type SkinStats struct {
-	GamesPlayed int
-	HoursPlayed int
-	CoinsEarned int64
+	GamesPlayed int		`json:"games_played"`
+	HoursPlayed int		`json:"hours_played"`
+	CoinsEarned int64	`json:"coins_earned"`
 }
 
 
@@ -5,5 +5,55 @@ import "errors"
 
 var(
 	ErrSkinIsNotOpened = errors.New("skin is not opened")
+	ErrNotEnoughCoins = errors.New("not enough coins")
+	ErrNotEnoughReferrals = errors.New("not enough referrals")
+	ErrLevelChanged = errors.New("character level was changed by another request")
+	ErrUnknownPaymentMethod = errors.New("unknown payment method")
+	ErrPaymentMethodUnavailable = errors.New("payment method is unavailable for this level")
 	ErrSkinIsNotExist = errors.New("skin is not exist")
+	ErrSkinAlreadyOwned = errors.New("skin is already owned")
+	ErrSkinUnavailable = errors.New("skin is unavailable")
+	ErrSkinNotPurchasable = errors.New("skin can only be received as a reward")
+	ErrSelfGift = errors.New("skin can not be gifted to yourself")
+	ErrGiftRecipientNotFound = errors.New("gift recipient has no character")
+	ErrGiftDailyLimitReached = errors.New("daily gift limit reached")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrDailyRewardAlreadyClaimed = errors.New("daily reward is already claimed")
+	ErrDailyRewardsDisabled = errors.New("daily rewards calendar is empty")
+	ErrUnknownLeaderboard = errors.New("unknown leaderboard")
+	ErrMiningClaimIDRequired = errors.New("mining claim id is required")
+	ErrUnknownReward = errors.New("unknown reward type")
+	ErrInvalidReward = errors.New("invalid reward")
+	ErrNoActiveSeason = errors.New("no active season")
+	ErrSeasonTierNotExist = errors.New("season tier is not exist")
+	ErrSeasonTierNotReached = errors.New("season tier is not reached")
+	ErrSeasonTierAlreadyClaimed = errors.New("season tier is already claimed")
+	ErrSeasonPremiumRequired = errors.New("season premium is required")
+	ErrSeasonPremiumAlreadyActive = errors.New("season premium is already active")
+	ErrSeasonPremiumUnavailable = errors.New("season premium price is not set")
+	ErrQuestNotClaimable = errors.New("quest is not completed or already claimed")
+	ErrQuestTemplateNotExist = errors.New("quest template is not exist")
+	ErrInvalidQuestTemplate = errors.New("invalid quest template")
+	ErrInvalidNickname = errors.New("invalid nickname")
+	ErrNicknameForbidden = errors.New("nickname contains forbidden words")
+	ErrNicknameTaken = errors.New("nickname is already taken")
+	ErrNicknameUnchanged = errors.New("nickname is the same")
+	ErrRenameCooldown = errors.New("rename is on cooldown")
+	ErrInvalidStatAllocation = errors.New("invalid stat allocation")
+	ErrNotEnoughStatPoints = errors.New("not enough free stat points")
+	ErrNothingToRespec = errors.New("no allocated stat points")
+	ErrItemIsNotExist = errors.New("item is not exist")
+	ErrItemNotOwned = errors.New("item is not in inventory")
+	ErrItemNotEquipped = errors.New("item is not equipped")
+	ErrInvalidItemQuantity = errors.New("invalid item quantity")
+	ErrPromoCodeIsNotExist = errors.New("promo code is not exist")
+	ErrPromoCodeExpired = errors.New("promo code is expired")
+	ErrPromoCodeExhausted = errors.New("promo code redemptions limit reached")
+	ErrPromoCodeAlreadyRedeemed = errors.New("promo code is already redeemed")
+	ErrPromoCodeLevelTooLow = errors.New("character level is too low for promo code")
+	ErrInvalidPromoCodeBatch = errors.New("invalid promo code batch")
+	ErrNoNextLevel = errors.New("no price for the next level")
+	ErrInvalidPriceCampaign = errors.New("invalid price campaign")
+	ErrPriceCampaignNotExist = errors.New("price campaign is not exist")
+	ErrOperationBlocked = errors.New("operation is blocked by fraud check")
 )
\ No newline at end of file
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+)
+
+type (
+	primaryKey   struct{}
+	isolationKey struct{}
+)
+
+// WithPrimary - marks context so reads go to the primary database instead of replicas.
+// Use it for reads right after own write, when replica may not have the change yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// IsPrimary - true if reads of the context must go to the primary database
+func IsPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// WithIsolation - isolation level for transactions started with the context, overrides configured default
+func WithIsolation(ctx context.Context, level sql.IsolationLevel) context.Context {
+	return context.WithValue(ctx, isolationKey{}, level)
+}
+
+// Isolation - isolation level set by WithIsolation, false if the context has none
+func Isolation(ctx context.Context) (sql.IsolationLevel, bool) {
+	level, ok := ctx.Value(isolationKey{}).(sql.IsolationLevel)
+	return level, ok
+}
@@ -0,0 +1,19 @@
+package storage
+
+import "errors"
+
+var (
+	ErrCharacterNotFound = errors.New("character not found")
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrSkinUnavailable = errors.New("skin is unavailable")
+	ErrNotEnoughReferrals = errors.New("not enough referral credits")
+	ErrLevelChanged = errors.New("character level was changed concurrently")
+	ErrQuestTemplateNotFound = errors.New("quest template not found")
+	ErrNicknameTaken = errors.New("nickname is already taken")
+	ErrItemNotFound = errors.New("item not found")
+	ErrItemNotOwned = errors.New("item is not in inventory")
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	ErrPromoCodeExhausted = errors.New("promo code redemptions limit reached")
+	ErrPromoCodeUserLimit = errors.New("promo code user limit reached")
+	ErrPriceCampaignNotFound = errors.New("price campaign not found")
+)
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+// AllocateStats - adds points to attributes if the character has enough free points for its level.
+// Returns nil if there are not enough free points.
+func (s *PostgresCharacterProvider) AllocateStats(ctx context.Context, userID int64, stats dto.AttributesDTO, pointsPerLevel int) (*dto.AttributesDTO, error) {
+	const op = "storage.postgres.AllocateStats"
+	dialect := goqu.Dialect("postgres")
+
+	// Свободные очки считаем от уровня в том же запросе, чтобы параллельные распределения не превысили лимит
+	updateQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{
+			"strength": goqu.L("strength + ?", stats.Strength),
+			"luck":     goqu.L("luck + ?", stats.Luck),
+			"stamina":  goqu.L("stamina + ?", stats.Stamina),
+		}).
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.L("strength + luck + stamina + ? <= (current_level - 1) * ?", stats.Total(), pointsPerLevel),
+		).
+		Returning("strength", "luck", "stamina")
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var attributes dto.AttributesDTO
+	if err := s.storage.conn().GetContext(ctx, &attributes, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &attributes, nil
+}
+
+// ResetStats - returns all allocated points back, returns false if nothing was allocated
+func (s *PostgresCharacterProvider) ResetStats(ctx context.Context, userID int64) (bool, error) {
+	const op = "storage.postgres.ResetStats"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{"strength": 0, "luck": 0, "stamina": 0}).
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.L("strength + luck + stamina > 0"),
+		)
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+)
+
+var rankingColumns = []interface{}{"user_id", "current_level", "prestige", "total_mined_coins"}
+
+func (s *PostgresCharacterProvider) GetCharacterRanking(ctx context.Context, userID int64) (*dto.CharacterRankingDTO, error) {
+	const op = "storage.postgres.GetCharacterRanking"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacters).
+		Select(rankingColumns...).
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var ranking dto.CharacterRankingDTO
+	err = s.storage.reader(ctx).GetContext(ctx, &ranking, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &ranking, nil
+}
+
+// GetCharactersRanking - returns next batch of characters ordered by user id, starting after afterUserID
+func (s *PostgresCharacterProvider) GetCharactersRanking(ctx context.Context, afterUserID int64, limit uint) ([]dto.CharacterRankingDTO, error) {
+	const op = "storage.postgres.GetCharactersRanking"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacters).
+		Select(rankingColumns...).
+		Where(goqu.C("user_id").Gt(afterUserID)).
+		Order(goqu.I("user_id").Asc()).
+		Limit(limit)
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var rankings []dto.CharacterRankingDTO
+	if err := s.storage.reader(ctx).SelectContext(ctx, &rankings, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return rankings, nil
+}
+
+// AddMinedCoins - increases total mined coins of character and returns new total.
+// Claim is recorded in the same statement, nil total means the claim was already counted.
+func (s *PostgresCharacterProvider) AddMinedCoins(ctx context.Context, userID int64, claimID string, coins int64) (*int64, error) {
+	const op = "storage.postgres.AddMinedCoins"
+	dialect := goqu.Dialect("postgres")
+
+	claimQuery := dialect.Insert(TableCharacterMiningClaims).
+		Rows(goqu.Record{"claim_id": claimID, "user_id": userID, "coins": coins}).
+		OnConflict(goqu.DoNothing()).
+		Returning("user_id", "coins")
+
+	updateQuery := dialect.Update(TableCharacters).
+		With("claim", claimQuery).
+		From(goqu.T("claim")).
+		Set(goqu.Record{"total_mined_coins": goqu.L("? + ?", goqu.I("characters.total_mined_coins"), goqu.I("claim.coins"))}).
+		Where(goqu.I("characters.user_id").Eq(goqu.I("claim.user_id"))).
+		Returning(goqu.I("characters.total_mined_coins"))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var total int64
+	err = s.storage.conn().GetContext(ctx, &total, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+			return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &total, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/lib/pq"
+)
+
+// Коды ошибок ограничений Postgres
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// GetNickname - returns nickname of the character and time of its last change
+func (s *PostgresCharacterProvider) GetNickname(ctx context.Context, userID int64) (*dto.NicknameDTO, error) {
+	const op = "storage.postgres.GetNickname"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacters).
+		Select("nickname", "nickname_changed_at").
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var nickname dto.NicknameDTO
+	if err := s.storage.conn().GetContext(ctx, &nickname, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &nickname, nil
+}
+
+// SetNickname - saves nickname if the previous change is older than cooldown, zero cooldown skips the check.
+// Returns false if cooldown is not over yet.
+func (s *PostgresCharacterProvider) SetNickname(ctx context.Context, userID int64, nickname string, cooldown time.Duration) (bool, error) {
+	const op = "storage.postgres.SetNickname"
+	dialect := goqu.Dialect("postgres")
+
+	conditions := []goqu.Expression{goqu.C("user_id").Eq(userID)}
+	if cooldown > 0 {
+		// Кулдаун проверяем по часам базы в том же запросе, чтобы параллельные переименования не прошли оба
+		conditions = append(conditions, goqu.Or(
+			goqu.C("nickname_changed_at").IsNull(),
+			goqu.L("nickname_changed_at <= CURRENT_TIMESTAMP - ? * INTERVAL '1 second'", int64(cooldown.Seconds())),
+		))
+	}
+
+	updateQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{
+			"nickname":            nickname,
+			"nickname_changed_at": goqu.L("CURRENT_TIMESTAMP"),
+		}).
+		Where(conditions...)
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return false, fmt.Errorf("%s: %w", op, ErrNicknameTaken)
+		}
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
@@ -34,7 +34,7 @@ func (s *PostgresCharacterProvider) CreateCharacter(ctx context.Context, userID
 		return fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
 
-	_, err = s.storage.db.ExecContext(ctx, query, args...)
+	_, err = s.storage.conn().ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("%s: failed to execute query: %w", op, err)
 	}
@@ -59,7 +59,7 @@ func (s *PostgresCharacterProvider) GetCharacterLevel(ctx context.Context, userI
         return &level, fmt.Errorf("%s: failed to build query: %w", op, err)
     }
 
-    err = s.storage.db.GetContext(ctx, &level, query, args...)
+    err = s.storage.reader(ctx).GetContext(ctx, &level, query, args...)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
             return &level, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
@@ -72,8 +72,101 @@ func (s *PostgresCharacterProvider) GetCharacterLevel(ctx context.Context, userI
 func (s *PostgresCharacterProvider) GetCharacter(ctx context.Context, userID int64) (*dto.GetCharacterDTO, error) {
     const op = "storage.postgres.getCharacter"
 
+    query, args, err := characterQuery(userID).ToSQL()
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+    }
+
+    var character dto.GetCharacterDTO
+    err = s.storage.reader(ctx).GetContext(ctx, &character, query, args...)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+        }
+        return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+    }
+
+    return &character, nil
+}
+
+// GetCharacterAggregate - returns character with owned skins, equipment, active boosts and version read from one snapshot
+func (s *PostgresCharacterProvider) GetCharacterAggregate(ctx context.Context, userID int64) (*dto.CharacterAggregateDTO, error) {
+	const op = "storage.postgres.GetCharacterAggregate"
+
+	// Все запросы видят один снимок, иначе версия может не соответствовать скинам, экипировке и бустам
+	tx, err := s.storage.beginRead(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := characterQuery(userID).SelectAppend("characters.version").ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var character struct {
+		dto.GetCharacterDTO
+		Version int64 `db:"version"`
+	}
+	if err := tx.GetContext(ctx, &character, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	query, args, err = goqu.Dialect("postgres").From(TableCharacterOwnedSkins).
+		Select("skin_id").
+		Where(goqu.C("user_id").Eq(userID)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var ownedSkins []int
+	if err := tx.SelectContext(ctx, &ownedSkins, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	query, args, err = inventoryQuery(userID).Where(goqu.I("ce.item_id").IsNotNull()).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	equipment := []dto.InventoryItemDTO{}
+	if err := tx.SelectContext(ctx, &equipment, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	query, args, err = activeBoostsQuery(userID).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	boosts := []dto.BoostDTO{}
+	if err := tx.SelectContext(ctx, &boosts, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+
+	return &dto.CharacterAggregateDTO{
+		UserID:     userID,
+		Version:    character.Version,
+		Character:  character.GetCharacterDTO,
+		OwnedSkins: ownedSkins,
+		Equipment:  equipment,
+		Boosts:     boosts,
+	}, nil
+}
+
+// characterQuery - selects character with its skin and level values
+func characterQuery(userID int64) *goqu.SelectDataset {
     dialect := goqu.Dialect("postgres")
-    selectQuery := dialect.From(TableCharacters).
+    return dialect.From(TableCharacters).
         LeftJoin(
             goqu.T("character_skins"),
             goqu.On(goqu.Ex{"characters.current_skin_id": goqu.I("character_skins.skin_id")}),
@@ -87,26 +180,15 @@ func (s *PostgresCharacterProvider) GetCharacter(ctx context.Context, userID int
             "characters.current_level",
             "character_skins.skin_id",
             "character_skins.character_name",
+            goqu.L("COALESCE(characters.nickname, '')").As("nickname"),
             "character_skins.character_image_url",
 			"character_levels.mining_force",
-			"character_levels.mining_duration_minutes",
+			goqu.I("character_levels.mining_duration_minuts").As("mining_duration_minutes"),
+			"character_levels.game_multiplayer",
+			"characters.strength",
+			"characters.luck",
+			"characters.stamina",
         )
-
-    query, args, err := selectQuery.ToSQL()
-    if err != nil {
-        return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
-    }
-
-    var character dto.GetCharacterDTO
-    err = s.storage.db.GetContext(ctx, &character, query, args...)
-    if err != nil {
-        if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
-        }
-        return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
-    }
-
-    return &character, nil
 }
 
 func (s *PostgresCharacterProvider) GetAllSkins(ctx context.Context) (*dto.GetSkinsDTO, error) {
@@ -129,6 +211,13 @@ func (s *PostgresCharacterProvider) GetAllSkins(ctx context.Context) (*dto.GetSk
             "character_levels.price",
             "character_levels.referrals",
             "character_levels.referral_to_open",
+            "character_skins.is_exclusive",
+            "character_skins.available_from",
+            "character_skins.available_until",
+            "character_skins.max_supply",
+            "character_skins.sold_count",
+            "character_skins.rarity",
+            "character_skins.collection_id",
         )
 
     sql, args, err := query.ToSQL()
@@ -136,7 +225,7 @@ func (s *PostgresCharacterProvider) GetAllSkins(ctx context.Context) (*dto.GetSk
         return &skins, fmt.Errorf("%s: failed to build query: %w", op, err)
     }
 
-    rows, err := s.storage.db.Query(sql, args...)
+    rows, err := s.storage.reader(ctx).QueryContext(ctx, sql, args...)
     if err != nil {
         return &skins, fmt.Errorf("%s: failed to executes a query: %w", op, err)
     }
@@ -153,6 +242,13 @@ func (s *PostgresCharacterProvider) GetAllSkins(ctx context.Context) (*dto.GetSk
             &skin.Price,
             &skin.RefToBuy,
             &skin.RefToOpen,
+            &skin.IsExclusive,
+            &skin.AvailableFrom,
+            &skin.AvailableUntil,
+            &skin.MaxSupply,
+            &skin.SoldCount,
+            &skin.Rarity,
+            &skin.CollectionID,
         )
         if err != nil {
             return &skins, err
@@ -173,6 +269,18 @@ func (s *PostgresCharacterProvider) GetAllSkins(ctx context.Context) (*dto.GetSk
         return &skins, err
     }
 
+    collectionsQuery := dialect.From(TableSkinCollections).
+        Select("collection_id", "name", "bonus_type", "bonus_percent")
+
+    sql, args, err = collectionsQuery.ToSQL()
+    if err != nil {
+        return &skins, fmt.Errorf("%s: failed to build query: %w", op, err)
+    }
+
+    if err := s.storage.reader(ctx).SelectContext(ctx, &skins.Collections, sql, args...); err != nil {
+        return &skins, fmt.Errorf("%s: failed to execute query: %w", op, err)
+    }
+
 	return &skins, nil
 }
 
@@ -191,7 +299,7 @@ func (s *PostgresCharacterProvider) GetAllLevelPrices(ctx context.Context) (*dto
     }
 
     var levels []dto.LevelPriceDTO
-    err = s.storage.db.SelectContext(ctx, &levels, sql, args...)
+    err = s.storage.reader(ctx).SelectContext(ctx, &levels, sql, args...)
     if err != nil {
         return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
     }
@@ -216,7 +324,7 @@ func (s *PostgresCharacterProvider) GetLevelPrice(ctx context.Context, level int
         return price, fmt.Errorf("%s: failed to build query: %w", op, err)
     }
 
-    err = s.storage.db.GetContext(ctx, &level, query, args...)
+    err = s.storage.reader(ctx).GetContext(ctx, &level, query, args...)
     if err != nil {
         if errors.Is(err, sql.ErrNoRows) {
             return price, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
@@ -226,7 +334,8 @@ func (s *PostgresCharacterProvider) GetLevelPrice(ctx context.Context, level int
 	return price, nil
 }
 
-func (s *PostgresCharacterProvider) UpgradeCharacterLevel(ctx context.Context, userID int64) (*int, error) {
+// UpgradeCharacterLevel - raises level by one if it is still fromLevel, otherwise returns ErrLevelChanged
+func (s *PostgresCharacterProvider) UpgradeCharacterLevel(ctx context.Context, userID int64, fromLevel int) (*int, error) {
 	const op = "storage.postgres.UpgradeCharacter"
 
 	dialect := goqu.Dialect("postgres")
@@ -234,11 +343,11 @@ func (s *PostgresCharacterProvider) UpgradeCharacterLevel(ctx context.Context, u
 	// Создаем запрос для увеличения уровня на 1 с использованием RETURNING для возврата нового уровня
 	updateQuery := dialect.Update(TableCharacters).
 		Set(goqu.Record{"current_level": goqu.L("current_level + 1")}).
-		Where(goqu.C("user_id").Eq(userID)).
+		Where(goqu.C("user_id").Eq(userID), goqu.C("current_level").Eq(fromLevel)).
 		Returning("current_level") // Добавляем RETURNING, чтобы сразу вернуть обновленное значение
 
 	// Преобразуем запрос в SQL
-	sql, args, err := updateQuery.ToSQL()
+	query, args, err := updateQuery.ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
 	}
@@ -247,8 +356,12 @@ func (s *PostgresCharacterProvider) UpgradeCharacterLevel(ctx context.Context, u
 	var currentLevel int
 
 	// Выполняем SQL-запрос, который сразу вернет новый уровень
-	err = s.storage.db.GetContext(ctx, &currentLevel, sql, args...)
+	err = s.storage.conn().GetContext(ctx, &currentLevel, query, args...)
 	if err != nil {
+		// Уровень уже повысил параллельный запрос, цена считалась для старого уровня
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrLevelChanged)
+		}
 		return nil, fmt.Errorf("%s: failed to upgrade and retrieve current level: %w", op, err)
 	}
 
@@ -272,10 +385,70 @@ func (s *PostgresCharacterProvider) ChangeActiveSkin(ctx context.Context, userID
     }
 
     // Выполняем запрос
-    _, err = s.storage.db.ExecContext(ctx, sql, args...)
+    _, err = s.storage.conn().ExecContext(ctx, sql, args...)
     if err != nil {
         return fmt.Errorf("%s: failed to execute query: %w", op, err)
     }
 
     return nil
 }
+
+// UpgradeCharacterLevelForReferrals - spends referral credits and increases level atomically,
+// level must still be fromLevel
+func (s *PostgresCharacterProvider) UpgradeCharacterLevelForReferrals(ctx context.Context, userID int64, fromLevel int, credits int64) (*int, error) {
+	const op = "storage.postgres.UpgradeCharacterLevelForReferrals"
+
+	dialect := goqu.Dialect("postgres")
+
+	spendQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{"referrals_spent": goqu.L("referrals_spent + ?", credits)}).
+		Where(
+			goqu.C("user_id").Eq(userID),
+			goqu.L("referrals_count - referrals_spent >= ?", credits),
+		)
+
+	spendSQL, spendArgs, err := spendQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	upgradeQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{"current_level": goqu.L("current_level + 1")}).
+		Where(goqu.C("user_id").Eq(userID), goqu.C("current_level").Eq(fromLevel)).
+		Returning("current_level")
+
+	upgradeSQL, upgradeArgs, err := upgradeQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var currentLevel int
+	err = s.storage.atomic(ctx, func(q queryer) error {
+		res, err := q.ExecContext(ctx, spendSQL, spendArgs...)
+		if err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("%s: %w", op, ErrNotEnoughReferrals)
+		}
+
+		if err := q.GetContext(ctx, &currentLevel, upgradeSQL, upgradeArgs...); err != nil {
+			// Откат транзакции возвращает и списанные кредиты
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%s: %w", op, ErrLevelChanged)
+			}
+			return fmt.Errorf("%s: failed to upgrade and retrieve current level: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &currentLevel, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+// GrantSkin - gives skin to the user. Ownership row and skin supply are updated in one transaction,
+// so limited skins can not be oversold and already owned skins do not consume supply.
+// Only sales check availability window and consume supply, earned rewards are granted regardless of them.
+func (s *PostgresCharacterProvider) GrantSkin(ctx context.Context, userID int64, skinID int, source string, sale bool) (*dto.SkinGrantDTO, error) {
+	const op = "storage.postgres.GrantSkin"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterOwnedSkins).
+		Rows(goqu.Record{"user_id": userID, "skin_id": skinID, "source": source}).
+		OnConflict(goqu.DoNothing())
+
+	insertSQL, insertArgs, err := insertQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	now := time.Now()
+	supplyQuery := dialect.Update(TableCharacterSkins).
+		Set(goqu.Record{"sold_count": goqu.L("sold_count + 1")}).
+		Where(
+			goqu.C("skin_id").Eq(skinID),
+			goqu.Or(goqu.C("available_from").IsNull(), goqu.C("available_from").Lte(now)),
+			goqu.Or(goqu.C("available_until").IsNull(), goqu.C("available_until").Gt(now)),
+			goqu.Or(goqu.C("max_supply").IsNull(), goqu.C("sold_count").Lt(goqu.C("max_supply"))),
+		).
+		Returning("max_supply", "sold_count")
+
+	supplySQL, supplyArgs, err := supplyQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var grant dto.SkinGrantDTO
+	err = s.storage.atomic(ctx, func(q queryer) error {
+		grant = dto.SkinGrantDTO{}
+
+		res, err := q.ExecContext(ctx, insertSQL, insertArgs...)
+		if err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+		}
+		if affected == 0 {
+			return nil
+		}
+
+		grant.Granted = true
+		if !sale {
+			return nil
+		}
+
+		var supply struct {
+			MaxSupply *int `db:"max_supply"`
+			SoldCount int  `db:"sold_count"`
+		}
+		err = q.GetContext(ctx, &supply, supplySQL, supplyArgs...)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%s: %w", op, ErrSkinUnavailable)
+			}
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		grant.MaxSupply = supply.MaxSupply
+		grant.SoldOut = supply.MaxSupply != nil && supply.SoldCount >= *supply.MaxSupply
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+// GetOwnedSkins - returns ids of skins granted to the user
+func (s *PostgresCharacterProvider) GetOwnedSkins(ctx context.Context, userID int64) ([]int, error) {
+	const op = "storage.postgres.GetOwnedSkins"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacterOwnedSkins).
+		Select("skin_id").
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var skins []int
+	if err := s.storage.reader(ctx).SelectContext(ctx, &skins, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return skins, nil
+}
+
+func (s *PostgresCharacterProvider) AddBoost(ctx context.Context, userID int64, boost dto.BoostDTO, source string) error {
+	const op = "storage.postgres.AddBoost"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterBoosts).
+		Rows(goqu.Record{
+			"user_id":    userID,
+			"boost_type": boost.Type,
+			"percent":    boost.Percent,
+			"source":     source,
+			"expires_at": boost.ExpiresAt,
+		})
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetActiveBoosts - returns not expired boosts of the user
+func (s *PostgresCharacterProvider) GetActiveBoosts(ctx context.Context, userID int64) ([]dto.BoostDTO, error) {
+	const op = "storage.postgres.GetActiveBoosts"
+
+	query, args, err := activeBoostsQuery(userID).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var boosts []dto.BoostDTO
+	if err := s.storage.reader(ctx).SelectContext(ctx, &boosts, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return boosts, nil
+}
+
+// activeBoostsQuery - selects not expired boosts of the user
+func activeBoostsQuery(userID int64) *goqu.SelectDataset {
+	dialect := goqu.Dialect("postgres")
+	return dialect.From(TableCharacterBoosts).
+		Select("boost_type", "percent", "expires_at").
+		Where(goqu.C("user_id").Eq(userID), goqu.C("expires_at").Gt(time.Now()))
+}
+
+func (s *PostgresCharacterProvider) CreateSkinGift(ctx context.Context, gift dto.SkinGiftDTO) error {
+	const op = "storage.postgres.CreateSkinGift"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableSkinGifts).Rows(gift)
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresDailyRewardProvider struct {
+	storage *Storage
+}
+
+func NewDailyRewardProvider(storage *Storage) *PostgresDailyRewardProvider {
+	return &PostgresDailyRewardProvider{
+		storage: storage,
+	}
+}
+
+// CreateDailyClaim - saves claim for the current day of the user timezone and continues streak from yesterday.
+// Current date is taken from database clock, so instances with skewed clocks agree on the day.
+// The previous claim is checked against the day in the timezone stored with it, so switching the timezone
+// east can not open the next day early. Returns nil if the day (or a later one after timezone change) is already claimed.
+func (s *PostgresDailyRewardProvider) CreateDailyClaim(ctx context.Context, userID int64, timezone string) (*dto.DailyClaimDTO, error) {
+	const op = "storage.postgres.CreateDailyClaim"
+
+	query := `
+		WITH today AS (
+			SELECT (CURRENT_TIMESTAMP AT TIME ZONE $2)::date AS claim_date
+		)
+		INSERT INTO ` + TableCharacterDailyClaims + ` (user_id, claim_date, streak, timezone)
+		SELECT $1, today.claim_date,
+			COALESCE((
+				SELECT streak FROM ` + TableCharacterDailyClaims + `
+				WHERE user_id = $1 AND claim_date = today.claim_date - 1
+			), 0) + 1,
+			$2
+		FROM today
+		WHERE NOT EXISTS (
+			SELECT 1 FROM ` + TableCharacterDailyClaims + `
+			WHERE user_id = $1 AND claim_date >= today.claim_date
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM (
+				SELECT claim_date, timezone FROM ` + TableCharacterDailyClaims + `
+				WHERE user_id = $1
+				ORDER BY claim_date DESC
+				LIMIT 1
+			) AS last_claim
+			WHERE last_claim.claim_date >= (CURRENT_TIMESTAMP AT TIME ZONE last_claim.timezone)::date
+		)
+		ON CONFLICT (user_id, claim_date) DO NOTHING
+		RETURNING claim_date, streak`
+
+	var claim dto.DailyClaimDTO
+	err := s.storage.conn().GetContext(ctx, &claim, query, userID, timezone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &claim, nil
+}
+
+// DeleteDailyClaim - removes claim, used when reward could not be granted
+func (s *PostgresDailyRewardProvider) DeleteDailyClaim(ctx context.Context, userID int64, claimDate time.Time) error {
+	const op = "storage.postgres.DeleteDailyClaim"
+	dialect := goqu.Dialect("postgres")
+
+	deleteQuery := dialect.Delete(TableCharacterDailyClaims).
+		Where(goqu.C("user_id").Eq(userID), goqu.C("claim_date").Eq(claimDate.Format(time.DateOnly)))
+
+	query, args, err := deleteQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetTimezone - returns stored timezone of the user, empty if it was never set
+func (s *PostgresDailyRewardProvider) GetTimezone(ctx context.Context, userID int64) (string, error) {
+	const op = "storage.postgres.GetTimezone"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacters).
+		Select(goqu.COALESCE(goqu.C("timezone"), "")).
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var timezone string
+	if err := s.storage.conn().GetContext(ctx, &timezone, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return "", fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return timezone, nil
+}
+
+func (s *PostgresDailyRewardProvider) SetTimezone(ctx context.Context, userID int64, timezone string) error {
+	const op = "storage.postgres.SetTimezone"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{"timezone": timezone}).
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
@@ -1,7 +1,19 @@
 package postgres
 
-import "errors"
+import "github.com/Silverman143/character-service/internal/storage"
 
 var (
-	ErrCharacterNotFound = errors.New("character not found")
-)
\ No newline at end of file
+	ErrCharacterNotFound = storage.ErrCharacterNotFound
+	ErrSeasonNotFound = storage.ErrSeasonNotFound
+	ErrSkinUnavailable = storage.ErrSkinUnavailable
+	ErrNotEnoughReferrals = storage.ErrNotEnoughReferrals
+	ErrLevelChanged = storage.ErrLevelChanged
+	ErrQuestTemplateNotFound = storage.ErrQuestTemplateNotFound
+	ErrNicknameTaken = storage.ErrNicknameTaken
+	ErrItemNotFound = storage.ErrItemNotFound
+	ErrItemNotOwned = storage.ErrItemNotOwned
+	ErrPromoCodeNotFound = storage.ErrPromoCodeNotFound
+	ErrPromoCodeExhausted = storage.ErrPromoCodeExhausted
+	ErrPromoCodeUserLimit = storage.ErrPromoCodeUserLimit
+	ErrPriceCampaignNotFound = storage.ErrPriceCampaignNotFound
+)
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresFraudProvider struct {
+	storage *Storage
+}
+
+func NewFraudProvider(storage *Storage) *PostgresFraudProvider {
+	return &PostgresFraudProvider{
+		storage: storage,
+	}
+}
+
+// FlagCharacter - flags character for review, repeated flag updates score and reasons
+func (s *PostgresFraudProvider) FlagCharacter(ctx context.Context, flag dto.FraudFlagDTO) error {
+	const op = "storage.postgres.FlagCharacter"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterFraudFlags).
+		Rows(goqu.Record{
+			"user_id":   flag.UserID,
+			"score":     flag.Score,
+			"reasons":   flag.Reasons,
+			"operation": flag.Operation,
+		}).
+		OnConflict(goqu.DoUpdate("user_id", goqu.Record{
+			"score":       goqu.L("EXCLUDED.score"),
+			"reasons":     goqu.L("EXCLUDED.reasons"),
+			"operation":   goqu.L("EXCLUDED.operation"),
+			"flags_count": goqu.L("?.flags_count + 1", goqu.T(TableCharacterFraudFlags)),
+			"updated_at":  goqu.L("CURRENT_TIMESTAMP"),
+		}))
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetFlaggedCharacters - returns flagged characters, recently flagged first
+func (s *PostgresFraudProvider) GetFlaggedCharacters(ctx context.Context, offset uint, limit uint) ([]dto.FraudFlagDTO, error) {
+	const op = "storage.postgres.GetFlaggedCharacters"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacterFraudFlags).
+		Select("user_id", "score", "reasons", "operation", "flags_count", "flagged_at", "updated_at").
+		Order(goqu.C("updated_at").Desc()).
+		Offset(offset).
+		Limit(limit)
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	flags := []dto.FraudFlagDTO{}
+	if err := s.storage.reader(ctx).SelectContext(ctx, &flags, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresInventoryProvider struct {
+	storage *Storage
+}
+
+func NewInventoryProvider(storage *Storage) *PostgresInventoryProvider {
+	return &PostgresInventoryProvider{
+		storage: storage,
+	}
+}
+
+// GetItem - returns item from the catalog
+func (s *PostgresInventoryProvider) GetItem(ctx context.Context, itemID int) (*dto.ItemDTO, error) {
+	const op = "storage.postgres.GetItem"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableItems).
+		Select("item_id", "code", "name", "description", "item_type", "slot", "image_url",
+			"mining_force_percent", "mining_duration_percent", "game_multiplier_percent", "is_active").
+		Where(goqu.C("item_id").Eq(itemID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var item dto.ItemDTO
+	if err := s.storage.reader(ctx).GetContext(ctx, &item, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &item, nil
+}
+
+// GetInventory - returns items of the user with quantities and equip status
+func (s *PostgresInventoryProvider) GetInventory(ctx context.Context, userID int64) ([]dto.InventoryItemDTO, error) {
+	const op = "storage.postgres.GetInventory"
+
+	query, args, err := inventoryQuery(userID).ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	inventory := []dto.InventoryItemDTO{}
+	if err := s.storage.reader(ctx).SelectContext(ctx, &inventory, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return inventory, nil
+}
+
+// inventoryQuery - selects owned items of the user with their equip status
+func inventoryQuery(userID int64) *goqu.SelectDataset {
+	dialect := goqu.Dialect("postgres")
+	return dialect.From(goqu.T(TableCharacterInventory).As("ci")).
+		Join(goqu.T(TableItems).As("i"), goqu.On(goqu.I("i.item_id").Eq(goqu.I("ci.item_id")))).
+		LeftJoin(goqu.T(TableCharacterEquipment).As("ce"), goqu.On(
+			goqu.I("ce.user_id").Eq(goqu.I("ci.user_id")),
+			goqu.I("ce.item_id").Eq(goqu.I("ci.item_id")),
+		)).
+		Select("i.item_id", "i.code", "i.name", "i.description", "i.item_type", "i.slot", "i.image_url",
+			"i.mining_force_percent", "i.mining_duration_percent", "i.game_multiplier_percent", "i.is_active",
+			"ci.quantity", goqu.L("ce.item_id IS NOT NULL").As("is_equipped")).
+		Where(goqu.I("ci.user_id").Eq(userID), goqu.I("ci.quantity").Gt(0)).
+		Order(goqu.I("i.item_id").Asc())
+}
+
+// GrantItem - adds quantity of the item to the user inventory, returns new quantity
+func (s *PostgresInventoryProvider) GrantItem(ctx context.Context, userID int64, itemID int, quantity int) (int, error) {
+	const op = "storage.postgres.GrantItem"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterInventory).
+		Rows(goqu.Record{"user_id": userID, "item_id": itemID, "quantity": quantity}).
+		OnConflict(goqu.DoUpdate("user_id, item_id", goqu.Record{
+			"quantity":   goqu.L("?.quantity + EXCLUDED.quantity", goqu.T(TableCharacterInventory)),
+			"updated_at": goqu.L("CURRENT_TIMESTAMP"),
+		})).
+		Returning("quantity")
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var total int
+	if err := s.storage.conn().GetContext(ctx, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return total, nil
+}
+
+// EquipItem - puts owned item into its slot, item previously equipped in the slot is replaced
+func (s *PostgresInventoryProvider) EquipItem(ctx context.Context, userID int64, item dto.ItemDTO) error {
+	const op = "storage.postgres.EquipItem"
+
+	// Предмет экипируется только если он есть в инвентаре, проверка и вставка в одном запросе
+	query := `
+		INSERT INTO ` + TableCharacterEquipment + ` (user_id, slot, item_id)
+		SELECT $1, $2, $3
+		WHERE EXISTS (
+			SELECT 1 FROM ` + TableCharacterInventory + `
+			WHERE user_id = $1 AND item_id = $3 AND quantity > 0
+		)
+		ON CONFLICT (user_id, slot) DO UPDATE
+		SET item_id = EXCLUDED.item_id, equipped_at = CURRENT_TIMESTAMP`
+
+	res, err := s.storage.conn().ExecContext(ctx, query, userID, item.Slot, item.ID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrItemNotOwned)
+	}
+
+	return nil
+}
+
+// UnequipItem - removes item from its slot, returns false if the item was not equipped
+func (s *PostgresInventoryProvider) UnequipItem(ctx context.Context, userID int64, itemID int) (bool, error) {
+	const op = "storage.postgres.UnequipItem"
+	dialect := goqu.Dialect("postgres")
+
+	deleteQuery := dialect.Delete(TableCharacterEquipment).
+		Where(goqu.C("user_id").Eq(userID), goqu.C("item_id").Eq(itemID))
+
+	query, args, err := deleteQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
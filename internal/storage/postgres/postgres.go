@@ -1,20 +1,47 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Silverman143/character-service/internal/config"
+	"github.com/Silverman143/character-service/internal/storage"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // Явно импортируем драйвер PostgreSQL
 )
 
 type Storage struct {
 	db *sqlx.DB
+	replicas *replicaSet
+	txConfig txConfig
+	// tx - transaction the storage is bound to, nil for the root storage
+	tx *txState
+}
+
+// queryer - part of sqlx.DB and sqlx.Tx used by providers
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// txn - read transaction opened by provider, savepoint when the storage is already bound to a transaction
+type txn interface {
+	queryer
+	Commit() error
+	Rollback() error
 }
 
 func New(cfg *config.PgSql) (*Storage, error) {
 	const op = "storage.postgres.postgres.NewDBConnection"
 
+	txConfig, err := newTxConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	connStr := fmt.Sprintf("user=%s dbname=%s sslmode=%s password=%s host=%s",
         cfg.User, cfg.DbName, cfg.SSLMode, cfg.Password, cfg.Host)
 
@@ -22,13 +49,73 @@ func New(cfg *config.PgSql) (*Storage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	return &Storage{db: db}, nil
+
+	replicas, err := newReplicaSet(cfg)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Storage{db: db, replicas: replicas, txConfig: txConfig}, nil
 }
 
 func (s *Storage) Stop() error {
+	s.replicas.close()
 	return s.db.Close()
 }
 
+// conn - database for queries that write or must see the latest data
+func (s *Storage) conn() queryer {
+	if s.tx != nil {
+		return s.tx.tx
+	}
+	return s.db
+}
+
+// reader - database for read-only queries: healthy replica or primary if there is none or the context requires primary.
+// Inside a transaction reads go to the transaction, so they see its writes.
+func (s *Storage) reader(ctx context.Context) queryer {
+	if s.tx != nil {
+		return s.tx.tx
+	}
+	return s.readerDB(ctx)
+}
+
+func (s *Storage) readerDB(ctx context.Context) *sqlx.DB {
+	if storage.IsPrimary(ctx) {
+		return s.db
+	}
+	if db := s.replicas.pick(); db != nil {
+		return db
+	}
+	return s.db
+}
+
+// beginRead - opens read-only transaction on the reader database
+func (s *Storage) beginRead(ctx context.Context, opts *sql.TxOptions) (txn, error) {
+	if s.tx != nil {
+		return s.tx.savepoint(ctx)
+	}
+	return s.readerDB(ctx).BeginTxx(ctx, opts)
+}
 
+// txConfig - defaults of transactions started with WithTx
+type txConfig struct {
+	isolation  sql.IsolationLevel
+	retries    int
+	retryDelay time.Duration
+}
 
+func newTxConfig(cfg *config.PgSql) (txConfig, error) {
+	levels := map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"read committed":  sql.LevelReadCommitted,
+		"repeatable read": sql.LevelRepeatableRead,
+		"serializable":    sql.LevelSerializable,
+	}
 
+	isolation, ok := levels[cfg.TxIsolation]
+	if !ok {
+		return txConfig{}, fmt.Errorf("unknown transaction isolation level %q", cfg.TxIsolation)
+	}
+	return txConfig{isolation: isolation, retries: cfg.TxRetries, retryDelay: cfg.TxRetryDelay}, nil
+}
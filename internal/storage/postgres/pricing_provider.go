@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+var campaignColumns = []interface{}{
+	"campaign_id", "name", "target", "target_id", "discount_type", "discount_value",
+	"min_level", "max_level", "min_prestige", "max_prestige", "starts_at", "ends_at", "is_active",
+}
+
+type PostgresPricingProvider struct {
+	storage *Storage
+}
+
+func NewPricingProvider(storage *Storage) *PostgresPricingProvider {
+	return &PostgresPricingProvider{
+		storage: storage,
+	}
+}
+
+// GetPriceCampaigns - returns active campaigns that are running at now or start before until
+func (s *PostgresPricingProvider) GetPriceCampaigns(ctx context.Context, now time.Time, until time.Time) ([]dto.PriceCampaignDTO, error) {
+	const op = "storage.postgres.GetPriceCampaigns"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TablePriceCampaigns).
+		Select(campaignColumns...).
+		Where(
+			goqu.C("is_active").IsTrue(),
+			goqu.C("ends_at").Gt(now),
+			goqu.C("starts_at").Lt(until),
+		)
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	campaigns := []dto.PriceCampaignDTO{}
+	if err := s.storage.reader(ctx).SelectContext(ctx, &campaigns, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return campaigns, nil
+}
+
+// GetAllPriceCampaigns - returns all campaigns for admin, newest first
+func (s *PostgresPricingProvider) GetAllPriceCampaigns(ctx context.Context) ([]dto.PriceCampaignDTO, error) {
+	const op = "storage.postgres.GetAllPriceCampaigns"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TablePriceCampaigns).
+		Select(campaignColumns...).
+		Order(goqu.C("starts_at").Desc())
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	campaigns := []dto.PriceCampaignDTO{}
+	if err := s.storage.reader(ctx).SelectContext(ctx, &campaigns, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return campaigns, nil
+}
+
+// CreatePriceCampaign - saves new campaign and returns its id
+func (s *PostgresPricingProvider) CreatePriceCampaign(ctx context.Context, campaign dto.PriceCampaignDTO) (int, error) {
+	const op = "storage.postgres.CreatePriceCampaign"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TablePriceCampaigns).
+		Rows(goqu.Record{
+			"name":           campaign.Name,
+			"target":         campaign.Target,
+			"target_id":      campaign.TargetID,
+			"discount_type":  campaign.DiscountType,
+			"discount_value": campaign.DiscountValue,
+			"min_level":      campaign.MinLevel,
+			"max_level":      campaign.MaxLevel,
+			"min_prestige":   campaign.MinPrestige,
+			"max_prestige":   campaign.MaxPrestige,
+			"starts_at":      campaign.StartsAt,
+			"ends_at":        campaign.EndsAt,
+			"is_active":      campaign.IsActive,
+		}).
+		Returning("campaign_id")
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var campaignID int
+	if err := s.storage.conn().GetContext(ctx, &campaignID, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return campaignID, nil
+}
+
+// SetPriceCampaignActive - enables or disables campaign
+func (s *PostgresPricingProvider) SetPriceCampaignActive(ctx context.Context, campaignID int, isActive bool) error {
+	const op = "storage.postgres.SetPriceCampaignActive"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TablePriceCampaigns).
+		Set(goqu.Record{"is_active": isActive}).
+		Where(goqu.C("campaign_id").Eq(campaignID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrPriceCampaignNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresPromoCodeProvider struct {
+	storage *Storage
+}
+
+func NewPromoCodeProvider(storage *Storage) *PostgresPromoCodeProvider {
+	return &PostgresPromoCodeProvider{
+		storage: storage,
+	}
+}
+
+// GetPromoCode - returns promo code with its rewards
+func (s *PostgresPromoCodeProvider) GetPromoCode(ctx context.Context, code string) (*dto.PromoCodeDTO, error) {
+	const op = "storage.postgres.GetPromoCode"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TablePromoCodes).
+		Select("promo_code_id", "code", "batch_id", "max_redemptions", "redemptions_count",
+			"per_user_limit", "min_level", "expires_at", "is_active").
+		Where(goqu.C("code").Eq(code))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var promo dto.PromoCodeDTO
+	if err := s.storage.conn().GetContext(ctx, &promo, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrPromoCodeNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	rewardsQuery := dialect.From(TablePromoCodeRewards).
+		Select("reward_type", "amount", "skin_id", "boost_type", "duration_minutes").
+		Where(goqu.C("promo_code_id").Eq(promo.ID))
+
+	query, args, err = rewardsQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if err := s.storage.conn().SelectContext(ctx, &promo.Rewards, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &promo, nil
+}
+
+// CreatePromoRedemption - saves redemption if global and per-user limits allow it and the code is still valid.
+// Returns id of the redemption.
+func (s *PostgresPromoCodeProvider) CreatePromoRedemption(ctx context.Context, promo dto.PromoCodeDTO, userID int64) (int64, error) {
+	const op = "storage.postgres.CreatePromoRedemption"
+	dialect := goqu.Dialect("postgres")
+
+	var redemptionID int64
+	err := s.storage.atomic(ctx, func(tx queryer) error {
+		// Обновление строки кода блокирует ее до конца транзакции, поэтому проверка лимита пользователя ниже не гоняется
+		updateQuery := dialect.Update(TablePromoCodes).
+			Set(goqu.Record{"redemptions_count": goqu.L("redemptions_count + 1")}).
+			Where(
+				goqu.C("promo_code_id").Eq(promo.ID),
+				goqu.C("is_active").IsTrue(),
+				goqu.Or(goqu.C("max_redemptions").IsNull(), goqu.L("redemptions_count < max_redemptions")),
+				goqu.Or(goqu.C("expires_at").IsNull(), goqu.L("expires_at > CURRENT_TIMESTAMP")),
+			)
+
+		query, args, err := updateQuery.ToSQL()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("%s: %w", op, ErrPromoCodeExhausted)
+		}
+
+		countQuery := dialect.From(TablePromoRedemptions).
+			Select(goqu.COUNT("*")).
+			Where(goqu.C("promo_code_id").Eq(promo.ID), goqu.C("user_id").Eq(userID))
+
+		query, args, err = countQuery.ToSQL()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		var redeemed int
+		if err := tx.GetContext(ctx, &redeemed, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+		if redeemed >= promo.PerUserLimit {
+			return fmt.Errorf("%s: %w", op, ErrPromoCodeUserLimit)
+		}
+
+		insertQuery := dialect.Insert(TablePromoRedemptions).
+			Rows(goqu.Record{"promo_code_id": promo.ID, "user_id": userID}).
+			Returning("redemption_id")
+
+		query, args, err = insertQuery.ToSQL()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		if err := tx.GetContext(ctx, &redemptionID, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return redemptionID, nil
+}
+
+// DeletePromoRedemption - removes redemption and returns it to the code limit, used when rewards could not be granted
+func (s *PostgresPromoCodeProvider) DeletePromoRedemption(ctx context.Context, redemptionID int64) error {
+	const op = "storage.postgres.DeletePromoRedemption"
+
+	query := `
+		WITH deleted AS (
+			DELETE FROM ` + TablePromoRedemptions + `
+			WHERE redemption_id = $1
+			RETURNING promo_code_id
+		)
+		UPDATE ` + TablePromoCodes + ` pc
+		SET redemptions_count = pc.redemptions_count - 1
+		FROM deleted
+		WHERE pc.promo_code_id = deleted.promo_code_id`
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, redemptionID); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreatePromoCodes - saves codes of the batch with their rewards, codes that already exist are skipped.
+// Returns saved codes.
+func (s *PostgresPromoCodeProvider) CreatePromoCodes(ctx context.Context, batch dto.PromoCodeBatchDTO, codes []string) ([]string, error) {
+	const op = "storage.postgres.CreatePromoCodes"
+	dialect := goqu.Dialect("postgres")
+
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	rows := make([]interface{}, 0, len(codes))
+	for _, code := range codes {
+		rows = append(rows, goqu.Record{
+			"code":            code,
+			"batch_id":        batch.BatchID,
+			"max_redemptions": batch.MaxRedemptions,
+			"per_user_limit":  batch.PerUserLimit,
+			"min_level":       batch.MinLevel,
+			"expires_at":      batch.ExpiresAt,
+		})
+	}
+
+	insertQuery := dialect.Insert(TablePromoCodes).
+		Rows(rows...).
+		OnConflict(goqu.DoNothing()).
+		Returning("promo_code_id", "code")
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var saved []string
+	err = s.storage.atomic(ctx, func(tx queryer) error {
+		saved = nil
+
+		var created []struct {
+			ID   int    `db:"promo_code_id"`
+			Code string `db:"code"`
+		}
+		if err := tx.SelectContext(ctx, &created, query, args...); err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		if len(created) == 0 {
+			return nil
+		}
+
+		rewardRows := make([]interface{}, 0, len(created)*len(batch.Rewards))
+		saved = make([]string, 0, len(created))
+		for _, promo := range created {
+			saved = append(saved, promo.Code)
+			for _, reward := range batch.Rewards {
+				rewardRows = append(rewardRows, goqu.Record{
+					"promo_code_id":    promo.ID,
+					"reward_type":      reward.Type,
+					"amount":           reward.Amount,
+					"skin_id":          reward.SkinID,
+					"boost_type":       reward.BoostType,
+					"duration_minutes": reward.DurationMinutes,
+				})
+			}
+		}
+
+		if len(rewardRows) > 0 {
+			rewardsQuery := dialect.Insert(TablePromoCodeRewards).Rows(rewardRows...)
+
+			rewardsSQL, rewardsArgs, err := rewardsQuery.ToSQL()
+			if err != nil {
+				return fmt.Errorf("%s: failed to build query: %w", op, err)
+			}
+
+			if _, err := tx.ExecContext(ctx, rewardsSQL, rewardsArgs...); err != nil {
+				return fmt.Errorf("%s: failed to execute query: %w", op, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+// questPeriodStart - start of the current quest period by database clock in UTC, weeks start on monday
+const questPeriodStart = `CASE qt.period
+		WHEN 'weekly' THEN date_trunc('week', CURRENT_TIMESTAMP AT TIME ZONE 'UTC')::date
+		ELSE (CURRENT_TIMESTAMP AT TIME ZONE 'UTC')::date
+	END`
+
+type PostgresQuestProvider struct {
+	storage *Storage
+}
+
+func NewQuestProvider(storage *Storage) *PostgresQuestProvider {
+	return &PostgresQuestProvider{
+		storage: storage,
+	}
+}
+
+// AssignQuests - assigns active templates to the user for current periods, already assigned ones are kept
+func (s *PostgresQuestProvider) AssignQuests(ctx context.Context, userID int64) error {
+	const op = "storage.postgres.AssignQuests"
+
+	query := `
+		INSERT INTO ` + TableCharacterQuests + ` (user_id, template_id, period_start, target)
+		SELECT $1, qt.template_id, ` + questPeriodStart + `, qt.target
+		FROM ` + TableQuestTemplates + ` qt
+		WHERE qt.is_active
+		ON CONFLICT (user_id, template_id, period_start) DO NOTHING`
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetActiveQuests - returns quests of the user for current periods
+func (s *PostgresQuestProvider) GetActiveQuests(ctx context.Context, userID int64) ([]dto.QuestDTO, error) {
+	const op = "storage.postgres.GetActiveQuests"
+
+	query := `
+		SELECT cq.quest_id, qt.template_id, qt.code, qt.title, qt.description, qt.period, qt.action,
+			cq.progress, cq.target, cq.period_start, cq.completed_at, cq.claimed_at,
+			qt.reward_type, qt.amount, qt.skin_id, qt.boost_type, qt.duration_minutes
+		FROM ` + TableCharacterQuests + ` cq
+		JOIN ` + TableQuestTemplates + ` qt ON qt.template_id = cq.template_id
+		WHERE cq.user_id = $1 AND cq.period_start = ` + questPeriodStart + `
+		ORDER BY qt.period, cq.quest_id`
+
+	quests := []dto.QuestDTO{}
+	if err := s.storage.conn().SelectContext(ctx, &quests, query, userID); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return quests, nil
+}
+
+// AddQuestProgress - adds progress to unfinished quests of the current periods tracking the action.
+// Returns number of updated quests.
+func (s *PostgresQuestProvider) AddQuestProgress(ctx context.Context, userID int64, action string, amount int) (int64, error) {
+	const op = "storage.postgres.AddQuestProgress"
+
+	query := `
+		UPDATE ` + TableCharacterQuests + ` cq
+		SET progress = LEAST(cq.progress + $3, cq.target),
+			completed_at = CASE WHEN cq.progress + $3 >= cq.target THEN CURRENT_TIMESTAMP ELSE NULL END
+		FROM ` + TableQuestTemplates + ` qt
+		WHERE qt.template_id = cq.template_id
+			AND cq.user_id = $1
+			AND qt.action = $2
+			AND cq.period_start = ` + questPeriodStart + `
+			AND cq.progress < cq.target`
+
+	res, err := s.storage.conn().ExecContext(ctx, query, userID, action, amount)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected, nil
+}
+
+// ClaimQuest - marks completed quest of the current period as claimed and returns its reward.
+// Returns nil if quest is not found, not completed or already claimed.
+func (s *PostgresQuestProvider) ClaimQuest(ctx context.Context, userID int64, questID int64) (*dto.RewardDTO, error) {
+	const op = "storage.postgres.ClaimQuest"
+
+	query := `
+		UPDATE ` + TableCharacterQuests + ` cq
+		SET claimed_at = CURRENT_TIMESTAMP
+		FROM ` + TableQuestTemplates + ` qt
+		WHERE qt.template_id = cq.template_id
+			AND cq.quest_id = $1
+			AND cq.user_id = $2
+			AND cq.period_start = ` + questPeriodStart + `
+			AND cq.progress >= cq.target
+			AND cq.claimed_at IS NULL
+		RETURNING qt.reward_type, qt.amount, qt.skin_id, qt.boost_type, qt.duration_minutes`
+
+	var reward dto.RewardDTO
+	if err := s.storage.conn().GetContext(ctx, &reward, query, questID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &reward, nil
+}
+
+// UnclaimQuest - resets quest claim, used when reward could not be granted
+func (s *PostgresQuestProvider) UnclaimQuest(ctx context.Context, userID int64, questID int64) error {
+	const op = "storage.postgres.UnclaimQuest"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableCharacterQuests).
+		Set(goqu.Record{"claimed_at": nil}).
+		Where(goqu.C("quest_id").Eq(questID), goqu.C("user_id").Eq(userID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetQuestTemplates - returns all quest templates including inactive ones
+func (s *PostgresQuestProvider) GetQuestTemplates(ctx context.Context) ([]dto.QuestTemplateDTO, error) {
+	const op = "storage.postgres.GetQuestTemplates"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableQuestTemplates).
+		Select("template_id", "code", "title", "description", "period", "action", "target", "is_active",
+			"reward_type", "amount", "skin_id", "boost_type", "duration_minutes").
+		Order(goqu.C("template_id").Asc())
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	templates := []dto.QuestTemplateDTO{}
+	if err := s.storage.reader(ctx).SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return templates, nil
+}
+
+// CreateQuestTemplate - saves new quest template and returns its id
+func (s *PostgresQuestProvider) CreateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) (int, error) {
+	const op = "storage.postgres.CreateQuestTemplate"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableQuestTemplates).
+		Rows(questTemplateRecord(template)).
+		Returning("template_id")
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var templateID int
+	if err := s.storage.conn().GetContext(ctx, &templateID, query, args...); err != nil {
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return templateID, nil
+}
+
+// UpdateQuestTemplate - updates quest template, already assigned quests keep their target
+func (s *PostgresQuestProvider) UpdateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) error {
+	const op = "storage.postgres.UpdateQuestTemplate"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableQuestTemplates).
+		Set(questTemplateRecord(template)).
+		Where(goqu.C("template_id").Eq(template.ID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	return s.execTemplateUpdate(ctx, op, query, args)
+}
+
+// SetQuestTemplateActive - enables or disables assignment of the template from the next period
+func (s *PostgresQuestProvider) SetQuestTemplateActive(ctx context.Context, templateID int, isActive bool) error {
+	const op = "storage.postgres.SetQuestTemplateActive"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableQuestTemplates).
+		Set(goqu.Record{"is_active": isActive}).
+		Where(goqu.C("template_id").Eq(templateID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	return s.execTemplateUpdate(ctx, op, query, args)
+}
+
+func (s *PostgresQuestProvider) execTemplateUpdate(ctx context.Context, op string, query string, args []interface{}) error {
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, ErrQuestTemplateNotFound)
+	}
+
+	return nil
+}
+
+func questTemplateRecord(template dto.QuestTemplateDTO) goqu.Record {
+	return goqu.Record{
+		"code":             template.Code,
+		"title":            template.Title,
+		"description":      template.Description,
+		"period":           template.Period,
+		"action":           template.Action,
+		"target":           template.Target,
+		"is_active":        template.IsActive,
+		"reward_type":      template.Type,
+		"amount":           template.Amount,
+		"skin_id":          template.SkinID,
+		"boost_type":       template.BoostType,
+		"duration_minutes": template.DurationMinutes,
+	}
+}
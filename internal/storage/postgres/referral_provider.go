@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresReferralProvider struct {
+	storage *Storage
+}
+
+func NewReferralProvider(storage *Storage) *PostgresReferralProvider {
+	return &PostgresReferralProvider{
+		storage: storage,
+	}
+}
+
+// AddReferral - saves referral of the user and increases local counter.
+// Returns current counter and false if the referral was already counted.
+func (s *PostgresReferralProvider) AddReferral(ctx context.Context, userID int64, referralUserID int64) (int, bool, error) {
+	const op = "storage.postgres.AddReferral"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterReferrals).
+		Rows(goqu.Record{"user_id": userID, "referral_user_id": referralUserID}).
+		OnConflict(goqu.DoNothing())
+
+	insertSQL, insertArgs, err := insertQuery.ToSQL()
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var (
+		count int
+		added bool
+	)
+	err = s.storage.atomic(ctx, func(q queryer) error {
+		res, err := q.ExecContext(ctx, insertSQL, insertArgs...)
+		if err != nil {
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+		}
+		added = affected > 0
+
+		increment := 0
+		if added {
+			increment = 1
+		}
+
+		updateQuery := dialect.Update(TableCharacters).
+			Set(goqu.Record{"referrals_count": goqu.L("referrals_count + ?", increment)}).
+			Where(goqu.C("user_id").Eq(userID)).
+			Returning("referrals_count")
+
+		updateSQL, updateArgs, err := updateQuery.ToSQL()
+		if err != nil {
+			return fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		if err := q.GetContext(ctx, &count, updateSQL, updateArgs...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+			}
+			return fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return count, added, nil
+}
+
+// GetReferralsCount - returns local referrals counter and whether it was synced with referral service
+func (s *PostgresReferralProvider) GetReferralsCount(ctx context.Context, userID int64) (int, bool, error) {
+	const op = "storage.postgres.GetReferralsCount"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacters).
+		Select("referrals_count", "referrals_synced").
+		Where(goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var counter struct {
+		Count  int  `db:"referrals_count"`
+		Synced bool `db:"referrals_synced"`
+	}
+	if err := s.storage.conn().GetContext(ctx, &counter, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return 0, false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return counter.Count, counter.Synced, nil
+}
+
+// SyncReferralsCount - raises local counter to the value from referral service and marks it as synced
+func (s *PostgresReferralProvider) SyncReferralsCount(ctx context.Context, userID int64, count int) (int, error) {
+	const op = "storage.postgres.SyncReferralsCount"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableCharacters).
+		Set(goqu.Record{
+			"referrals_count":  goqu.L("GREATEST(referrals_count, ?)", count),
+			"referrals_synced": true,
+		}).
+		Where(goqu.C("user_id").Eq(userID)).
+		Returning("referrals_count")
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var synced int
+	if err := s.storage.conn().GetContext(ctx, &synced, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrCharacterNotFound)
+		}
+		return 0, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return synced, nil
+}
+
+// CreateReferralMilestone - saves granted milestone, returns false if it was already granted
+func (s *PostgresReferralProvider) CreateReferralMilestone(ctx context.Context, userID int64, referrals int) (bool, error) {
+	const op = "storage.postgres.CreateReferralMilestone"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterReferralMilestones).
+		Rows(goqu.Record{"user_id": userID, "referrals": referrals}).
+		OnConflict(goqu.DoNothing())
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteReferralMilestone - removes milestone record, used when reward could not be granted
+func (s *PostgresReferralProvider) DeleteReferralMilestone(ctx context.Context, userID int64, referrals int) error {
+	const op = "storage.postgres.DeleteReferralMilestone"
+	dialect := goqu.Dialect("postgres")
+
+	deleteQuery := dialect.Delete(TableCharacterReferralMilestones).
+		Where(goqu.C("user_id").Eq(userID), goqu.C("referrals").Eq(referrals))
+
+	query, args, err := deleteQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
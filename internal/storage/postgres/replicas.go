@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/config"
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaLagQuery - seconds since last replayed transaction. While WAL receiver is streaming, zero is returned
+// when replica has replayed all received WAL, otherwise idle primary would look like a lagging replica.
+// Replica with disconnected receiver receives nothing, so its lag is always counted from the last replay.
+// Status of the receiver is visible only to roles with pg_read_all_stats.
+const replicaLagQuery = `
+SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming')
+		AND pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 'Infinity')
+END`
+
+type replica struct {
+	db      *sqlx.DB
+	name    string
+	healthy atomic.Bool
+}
+
+// replicaSet - read replicas with health state, reads are spread between healthy replicas by round robin
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+	maxLag   time.Duration
+}
+
+func newReplicaSet(cfg *config.PgSql) (*replicaSet, error) {
+	set := &replicaSet{interval: cfg.ReplicaCheckInterval, maxLag: cfg.ReplicaMaxLag}
+
+	for i, dsn := range cfg.ReplicaDSNs {
+		// Соединение ленивое: недоступная при старте реплика не мешает запуску, она просто не получит чтений
+		db, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("replica %d: %w", i, err)
+		}
+		set.replicas = append(set.replicas, &replica{db: db, name: fmt.Sprintf("replica-%d", i)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), set.period())
+	defer cancel()
+	for _, r := range set.replicas {
+		r.healthy.Store(set.check(ctx, r) == nil)
+	}
+	return set, nil
+}
+
+// RunHealthChecks - periodically checks replicas and takes lagging or unavailable ones out of reads until ctx is done
+func (s *Storage) RunHealthChecks(ctx context.Context, log *slog.Logger) {
+	const op = "storage.postgres.RunHealthChecks"
+	logger := log.With("op", op)
+
+	set := s.replicas
+	if len(set.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(set.period())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, set.period())
+		for _, r := range set.replicas {
+			err := set.check(checkCtx, r)
+			healthy := err == nil
+			if r.healthy.Swap(healthy) == healthy {
+				continue
+			}
+			if healthy {
+				logger.Info("replica is back in reads", "replica", r.name)
+			} else {
+				logger.Warn("replica is taken out of reads", "replica", r.name, "error", err)
+			}
+		}
+		cancel()
+	}
+}
+
+// pick - next healthy replica, nil if all are down
+func (set *replicaSet) pick() *sqlx.DB {
+	count := uint64(len(set.replicas))
+	if count == 0 {
+		return nil
+	}
+
+	start := set.next.Add(1)
+	for i := uint64(0); i < count; i++ {
+		r := set.replicas[(start+i)%count]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+func (set *replicaSet) check(ctx context.Context, r *replica) error {
+	var lag float64
+	if err := r.db.GetContext(ctx, &lag, replicaLagQuery); err != nil {
+		return err
+	}
+	if set.maxLag > 0 && lag > set.maxLag.Seconds() {
+		return fmt.Errorf("replication lag %.1fs exceeds %s", lag, set.maxLag)
+	}
+	return nil
+}
+
+// period - interval between checks, one round of checks must also finish within it
+func (set *replicaSet) period() time.Duration {
+	if set.interval <= 0 {
+		return 5 * time.Second
+	}
+	return set.interval
+}
+
+func (set *replicaSet) close() {
+	for _, r := range set.replicas {
+		r.db.Close()
+	}
+}
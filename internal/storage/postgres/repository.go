@@ -1,17 +1,42 @@
 package postgres
 
 import (
+	"context"
+
 	"github.com/Silverman143/character-service/internal/storage"
 )
 
 type Repository struct {
     storage.IAppProvider
     storage.ICharacterProvider
+    storage.ISeasonProvider
+    storage.IReferralProvider
+    storage.IDailyRewardProvider
+    storage.IQuestProvider
+    storage.IInventoryProvider
+    storage.IPromoCodeProvider
+    storage.IPricingProvider
+    storage.IFraudProvider
+    storage *Storage
 }
 
 func NewRepository(st *Storage) *Repository {
     return &Repository{
         IAppProvider:  NewAppProvider(st),
         ICharacterProvider: NewCharacterProvider(st),
+        ISeasonProvider: NewSeasonProvider(st),
+        IReferralProvider: NewReferralProvider(st),
+        IDailyRewardProvider: NewDailyRewardProvider(st),
+        IQuestProvider: NewQuestProvider(st),
+        IInventoryProvider: NewInventoryProvider(st),
+        IPromoCodeProvider: NewPromoCodeProvider(st),
+        IPricingProvider: NewPricingProvider(st),
+        IFraudProvider: NewFraudProvider(st),
+        storage: st,
     }
+}
+
+// WithTx - runs fn with providers bound to one transaction, see Storage.WithTx
+func (r *Repository) WithTx(ctx context.Context, fn func(repo storage.TxRepository) error) error {
+    return r.storage.WithTx(ctx, fn)
 }
\ No newline at end of file
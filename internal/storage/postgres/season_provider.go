@@ -0,0 +1,305 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/services/character/dto"
+	"github.com/doug-martin/goqu/v9"
+)
+
+type PostgresSeasonProvider struct {
+	storage *Storage
+}
+
+func NewSeasonProvider(storage *Storage) *PostgresSeasonProvider {
+	return &PostgresSeasonProvider{
+		storage: storage,
+	}
+}
+
+// GetActiveSeason - returns active season with all tiers and rewards
+func (s *PostgresSeasonProvider) GetActiveSeason(ctx context.Context) (*dto.SeasonDTO, error) {
+	const op = "storage.postgres.GetActiveSeason"
+	dialect := goqu.Dialect("postgres")
+
+	seasonQuery := dialect.From(TableSeasons).
+		Select("season_id", "name", "starts_at", "ends_at", "premium_price", "premium_free", "status").
+		Where(goqu.C("status").Eq(dto.SeasonActive)).
+		Order(goqu.I("starts_at").Desc()).
+		Limit(1)
+
+	query, args, err := seasonQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var season dto.SeasonDTO
+	err = s.storage.conn().GetContext(ctx, &season, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrSeasonNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	tiersQuery := dialect.From(TableSeasonTiers).
+		Select("tier_number", "xp_required").
+		Where(goqu.C("season_id").Eq(season.ID)).
+		Order(goqu.I("tier_number").Asc())
+
+	query, args, err = tiersQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if err := s.storage.conn().SelectContext(ctx, &season.Tiers, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	rewardsQuery := dialect.From(TableSeasonTierRewards).
+		Select("tier_number", "is_premium", "reward_type", "amount", "skin_id", "boost_type", "duration_minutes").
+		Where(goqu.C("season_id").Eq(season.ID))
+
+	query, args, err = rewardsQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var rewards []dto.SeasonTierRewardDTO
+	if err := s.storage.conn().SelectContext(ctx, &rewards, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	for _, r := range rewards {
+		for i := range season.Tiers {
+			if season.Tiers[i].Number != r.TierNumber {
+				continue
+			}
+			reward := r.RewardDTO
+			if r.IsPremium {
+				season.Tiers[i].PremiumReward = &reward
+			} else {
+				season.Tiers[i].FreeReward = &reward
+			}
+		}
+	}
+
+	return &season, nil
+}
+
+// GetSeasonProgress - returns user progress in the season, empty progress if user has no xp yet
+func (s *PostgresSeasonProvider) GetSeasonProgress(ctx context.Context, seasonID int, userID int64) (*dto.SeasonProgressDTO, error) {
+	const op = "storage.postgres.GetSeasonProgress"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableCharacterSeasonProgress).
+		Select("user_id", "season_id", "xp", "is_premium").
+		Where(goqu.C("season_id").Eq(seasonID), goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	progress := dto.SeasonProgressDTO{UserID: userID, SeasonID: seasonID}
+	err = s.storage.reader(ctx).GetContext(ctx, &progress, query, args...)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return &progress, nil
+}
+
+func (s *PostgresSeasonProvider) GetSeasonClaims(ctx context.Context, seasonID int, userID int64) ([]dto.SeasonTierClaimDTO, error) {
+	const op = "storage.postgres.GetSeasonClaims"
+	dialect := goqu.Dialect("postgres")
+
+	selectQuery := dialect.From(TableSeasonTierClaims).
+		Select("tier_number", "is_premium").
+		Where(goqu.C("season_id").Eq(seasonID), goqu.C("user_id").Eq(userID))
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	var claims []dto.SeasonTierClaimDTO
+	if err := s.storage.reader(ctx).SelectContext(ctx, &claims, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// AddSeasonXP - increases user xp in the season
+func (s *PostgresSeasonProvider) AddSeasonXP(ctx context.Context, seasonID int, userID int64, xp int64) error {
+	const op = "storage.postgres.AddSeasonXP"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterSeasonProgress).
+		Rows(goqu.Record{"season_id": seasonID, "user_id": userID, "xp": xp}).
+		OnConflict(goqu.DoUpdate("season_id, user_id", goqu.Record{
+			"xp":         goqu.L("? + EXCLUDED.xp", goqu.I(TableCharacterSeasonProgress+".xp")),
+			"updated_at": goqu.L("CURRENT_TIMESTAMP"),
+		}))
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// SetSeasonPremium - enables premium track for the user, returns false if it was already enabled
+func (s *PostgresSeasonProvider) SetSeasonPremium(ctx context.Context, seasonID int, userID int64) (bool, error) {
+	const op = "storage.postgres.SetSeasonPremium"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableCharacterSeasonProgress).
+		Rows(goqu.Record{"season_id": seasonID, "user_id": userID, "is_premium": true}).
+		OnConflict(goqu.DoUpdate("season_id, user_id", goqu.Record{
+			"is_premium": true,
+			"updated_at": goqu.L("CURRENT_TIMESTAMP"),
+		}).Where(goqu.I(TableCharacterSeasonProgress + ".is_premium").IsFalse()))
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// UnsetSeasonPremium - disables premium track, used to compensate failed payment
+func (s *PostgresSeasonProvider) UnsetSeasonPremium(ctx context.Context, seasonID int, userID int64) error {
+	const op = "storage.postgres.UnsetSeasonPremium"
+	dialect := goqu.Dialect("postgres")
+
+	updateQuery := dialect.Update(TableCharacterSeasonProgress).
+		Set(goqu.Record{"is_premium": false, "updated_at": goqu.L("CURRENT_TIMESTAMP")}).
+		Where(goqu.C("season_id").Eq(seasonID), goqu.C("user_id").Eq(userID))
+
+	query, args, err := updateQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// CreateSeasonClaim - saves tier claim, returns false if the tier was already claimed
+func (s *PostgresSeasonProvider) CreateSeasonClaim(ctx context.Context, seasonID int, userID int64, tier int, isPremium bool) (bool, error) {
+	const op = "storage.postgres.CreateSeasonClaim"
+	dialect := goqu.Dialect("postgres")
+
+	insertQuery := dialect.Insert(TableSeasonTierClaims).
+		Rows(goqu.Record{"season_id": seasonID, "user_id": userID, "tier_number": tier, "is_premium": isPremium}).
+		OnConflict(goqu.DoNothing())
+
+	query, args, err := insertQuery.ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	res, err := s.storage.conn().ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteSeasonClaim - removes tier claim, used when reward could not be granted
+func (s *PostgresSeasonProvider) DeleteSeasonClaim(ctx context.Context, seasonID int, userID int64, tier int, isPremium bool) error {
+	const op = "storage.postgres.DeleteSeasonClaim"
+	dialect := goqu.Dialect("postgres")
+
+	deleteQuery := dialect.Delete(TableSeasonTierClaims).
+		Where(
+			goqu.C("season_id").Eq(seasonID),
+			goqu.C("user_id").Eq(userID),
+			goqu.C("tier_number").Eq(tier),
+			goqu.C("is_premium").Eq(isPremium),
+		)
+
+	query, args, err := deleteQuery.ToSQL()
+	if err != nil {
+		return fmt.Errorf("%s: failed to build query: %w", op, err)
+	}
+
+	if _, err := s.storage.conn().ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: failed to execute query: %w", op, err)
+	}
+
+	return nil
+}
+
+// RolloverSeasons - finishes ended seasons and activates started ones, returns number of changed seasons
+func (s *PostgresSeasonProvider) RolloverSeasons(ctx context.Context, now time.Time) (int64, error) {
+	const op = "storage.postgres.RolloverSeasons"
+	dialect := goqu.Dialect("postgres")
+
+	finishQuery := dialect.Update(TableSeasons).
+		Set(goqu.Record{"status": dto.SeasonFinished}).
+		Where(
+			goqu.C("status").In(dto.SeasonScheduled, dto.SeasonActive),
+			goqu.C("ends_at").Lte(now),
+		)
+
+	activateQuery := dialect.Update(TableSeasons).
+		Set(goqu.Record{"status": dto.SeasonActive}).
+		Where(
+			goqu.C("status").Eq(dto.SeasonScheduled),
+			goqu.C("starts_at").Lte(now),
+			goqu.C("ends_at").Gt(now),
+		)
+
+	var changed int64
+	for _, q := range []*goqu.UpdateDataset{finishQuery, activateQuery} {
+		query, args, err := q.ToSQL()
+		if err != nil {
+			return changed, fmt.Errorf("%s: failed to build query: %w", op, err)
+		}
+
+		res, err := s.storage.conn().ExecContext(ctx, query, args...)
+		if err != nil {
+			return changed, fmt.Errorf("%s: failed to execute query: %w", op, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return changed, fmt.Errorf("%s: failed to get affected rows: %w", op, err)
+		}
+		changed += affected
+	}
+
+	return changed, nil
+}
@@ -5,4 +5,27 @@ const (
 	TableCharacterSkins = "character_skins"
 	TableCharacters = "characters"
 	TableCharacetrChangesLogs = "character_change_log"
-)
\ No newline at end of file
+	TableCharacterOwnedSkins = "character_owned_skins"
+	TableCharacterBoosts = "character_boosts"
+	TableSeasons = "seasons"
+	TableSeasonTiers = "season_tiers"
+	TableSeasonTierRewards = "season_tier_rewards"
+	TableCharacterSeasonProgress = "character_season_progress"
+	TableSeasonTierClaims = "season_tier_claims"
+	TableSkinCollections = "skin_collections"
+	TableSkinGifts = "skin_gifts"
+	TableCharacterReferrals = "character_referrals"
+	TableCharacterReferralMilestones = "character_referral_milestones"
+	TableCharacterDailyClaims = "character_daily_claims"
+	TableQuestTemplates = "quest_templates"
+	TableCharacterQuests = "character_quests"
+	TableItems = "items"
+	TableCharacterInventory = "character_inventory"
+	TableCharacterEquipment = "character_equipment"
+	TablePromoCodes = "promo_codes"
+	TablePromoCodeRewards = "promo_code_rewards"
+	TablePromoRedemptions = "promo_redemptions"
+	TablePriceCampaigns = "price_campaigns"
+	TableCharacterFraudFlags = "character_fraud_flags"
+	TableCharacterMiningClaims = "character_mining_claims"
+)
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Silverman143/character-service/internal/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Коды ошибок Postgres, после которых транзакцию можно повторить целиком
+const (
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+)
+
+// txState - transaction shared by the bound storage and all its providers, not safe for concurrent use
+type txState struct {
+	tx         *sqlx.Tx
+	savepoints int
+}
+
+// savepoint - nested transaction, commit releases the savepoint and rollback returns to it
+type savepoint struct {
+	*sqlx.Tx
+	ctx  context.Context
+	name string
+	done bool
+}
+
+func (t *txState) savepoint(ctx context.Context) (*savepoint, error) {
+	t.savepoints++
+	name := fmt.Sprintf("sp_%d", t.savepoints)
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	return &savepoint{Tx: t.tx, ctx: ctx, name: name}, nil
+}
+
+func (sp *savepoint) Commit() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	_, err := sp.Tx.ExecContext(sp.ctx, "RELEASE SAVEPOINT "+sp.name)
+	return err
+}
+
+func (sp *savepoint) Rollback() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	_, err := sp.Tx.ExecContext(sp.ctx, "ROLLBACK TO SAVEPOINT "+sp.name)
+	return err
+}
+
+// bind - storage running all queries in the transaction
+func (s *Storage) bind(tx *sqlx.Tx) *Storage {
+	return &Storage{db: s.db, replicas: s.replicas, txConfig: s.txConfig, tx: &txState{tx: tx}}
+}
+
+// WithTx - runs fn with providers bound to one transaction on the primary.
+// Isolation is taken from storage.WithIsolation or config, failed to serialize transaction is retried with backoff.
+func (s *Storage) WithTx(ctx context.Context, fn func(repo storage.TxRepository) error) error {
+	if s.tx != nil {
+		return s.runSavepoint(ctx, fn)
+	}
+
+	opts := &sql.TxOptions{Isolation: s.txConfig.isolation}
+	if level, ok := storage.Isolation(ctx); ok {
+		opts.Isolation = level
+	}
+
+	delay := s.txConfig.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := s.runTx(ctx, opts, fn)
+		if err == nil || attempt >= s.txConfig.retries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (s *Storage) runTx(ctx context.Context, opts *sql.TxOptions, fn func(repo storage.TxRepository) error) error {
+	const op = "storage.postgres.WithTx"
+
+	tx, err := s.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Ошибку fn возвращаем как есть, вызывающий код проверяет свои ошибки через errors.Is
+	if err := fn(NewRepository(s.bind(tx))); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit transaction: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) runSavepoint(ctx context.Context, fn func(repo storage.TxRepository) error) error {
+	const op = "storage.postgres.WithTx"
+
+	sp, err := s.tx.savepoint(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to create savepoint: %w", op, err)
+	}
+	defer sp.Rollback()
+
+	if err := fn(NewRepository(s)); err != nil {
+		return err
+	}
+
+	if err := sp.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to release savepoint: %w", op, err)
+	}
+	return nil
+}
+
+// atomic - runs fn of the provider in the unit of work: savepoint when the storage is bound to a transaction,
+// otherwise new transaction started as WithTx does. Fn may be run again, so it must only query q.
+func (s *Storage) atomic(ctx context.Context, fn func(q queryer) error) error {
+	return s.WithTx(ctx, func(repo storage.TxRepository) error {
+		return fn(repo.(*Repository).storage.conn())
+	})
+}
+
+// isRetryable - transaction failed because of concurrent transactions and can succeed if run again
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pqSerializationFailure || pqErr.Code == pqDeadlockDetected
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/Silverman143/character-service/internal/domain/models"
 	"github.com/Silverman143/character-service/internal/services/character/dto"
@@ -16,9 +17,111 @@ type ICharacterProvider interface {
 	GetCharacterLevel(ctx context.Context, userID int64) (*int, error)
 	CreateCharacter(ctx context.Context, userID int64) error
 	GetCharacter(ctx context.Context, userID int64) (*dto.GetCharacterDTO, error)
+	GetCharacterAggregate(ctx context.Context, userID int64) (*dto.CharacterAggregateDTO, error)
 	GetAllSkins(ctx context.Context) (*dto.GetSkinsDTO, error)
 	GetAllLevelPrices(ctx context.Context) (*dto.LevelPriceListDTO, error)
 	GetLevelPrice(ctx context.Context, level int16) (*int64, error)
-	UpgradeCharacterLevel(ctx context.Context, userID int64) (*int, error)
+	UpgradeCharacterLevel(ctx context.Context, userID int64, fromLevel int) (*int, error)
+	UpgradeCharacterLevelForReferrals(ctx context.Context, userID int64, fromLevel int, credits int64) (*int, error)
 	ChangeActiveSkin(ctx context.Context, userID int64, skinID int32) error
-}
\ No newline at end of file
+	GetCharacterRanking(ctx context.Context, userID int64) (*dto.CharacterRankingDTO, error)
+	GetCharactersRanking(ctx context.Context, afterUserID int64, limit uint) ([]dto.CharacterRankingDTO, error)
+	AddMinedCoins(ctx context.Context, userID int64, claimID string, coins int64) (*int64, error)
+	GrantSkin(ctx context.Context, userID int64, skinID int, source string, sale bool) (*dto.SkinGrantDTO, error)
+	GetOwnedSkins(ctx context.Context, userID int64) ([]int, error)
+	AddBoost(ctx context.Context, userID int64, boost dto.BoostDTO, source string) error
+	GetActiveBoosts(ctx context.Context, userID int64) ([]dto.BoostDTO, error)
+	CreateSkinGift(ctx context.Context, gift dto.SkinGiftDTO) error
+	GetNickname(ctx context.Context, userID int64) (*dto.NicknameDTO, error)
+	SetNickname(ctx context.Context, userID int64, nickname string, cooldown time.Duration) (bool, error)
+	AllocateStats(ctx context.Context, userID int64, stats dto.AttributesDTO, pointsPerLevel int) (*dto.AttributesDTO, error)
+	ResetStats(ctx context.Context, userID int64) (bool, error)
+}
+
+type ISeasonProvider interface {
+	GetActiveSeason(ctx context.Context) (*dto.SeasonDTO, error)
+	GetSeasonProgress(ctx context.Context, seasonID int, userID int64) (*dto.SeasonProgressDTO, error)
+	GetSeasonClaims(ctx context.Context, seasonID int, userID int64) ([]dto.SeasonTierClaimDTO, error)
+	AddSeasonXP(ctx context.Context, seasonID int, userID int64, xp int64) error
+	SetSeasonPremium(ctx context.Context, seasonID int, userID int64) (bool, error)
+	UnsetSeasonPremium(ctx context.Context, seasonID int, userID int64) error
+	CreateSeasonClaim(ctx context.Context, seasonID int, userID int64, tier int, isPremium bool) (bool, error)
+	DeleteSeasonClaim(ctx context.Context, seasonID int, userID int64, tier int, isPremium bool) error
+	RolloverSeasons(ctx context.Context, now time.Time) (int64, error)
+}
+
+type IReferralProvider interface {
+	AddReferral(ctx context.Context, userID int64, referralUserID int64) (int, bool, error)
+	GetReferralsCount(ctx context.Context, userID int64) (int, bool, error)
+	SyncReferralsCount(ctx context.Context, userID int64, count int) (int, error)
+	CreateReferralMilestone(ctx context.Context, userID int64, referrals int) (bool, error)
+	DeleteReferralMilestone(ctx context.Context, userID int64, referrals int) error
+}
+
+type IDailyRewardProvider interface {
+	CreateDailyClaim(ctx context.Context, userID int64, timezone string) (*dto.DailyClaimDTO, error)
+	DeleteDailyClaim(ctx context.Context, userID int64, claimDate time.Time) error
+	GetTimezone(ctx context.Context, userID int64) (string, error)
+	SetTimezone(ctx context.Context, userID int64, timezone string) error
+}
+
+type IQuestProvider interface {
+	AssignQuests(ctx context.Context, userID int64) error
+	GetActiveQuests(ctx context.Context, userID int64) ([]dto.QuestDTO, error)
+	AddQuestProgress(ctx context.Context, userID int64, action string, amount int) (int64, error)
+	ClaimQuest(ctx context.Context, userID int64, questID int64) (*dto.RewardDTO, error)
+	UnclaimQuest(ctx context.Context, userID int64, questID int64) error
+	GetQuestTemplates(ctx context.Context) ([]dto.QuestTemplateDTO, error)
+	CreateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) (int, error)
+	UpdateQuestTemplate(ctx context.Context, template dto.QuestTemplateDTO) error
+	SetQuestTemplateActive(ctx context.Context, templateID int, isActive bool) error
+}
+
+type IInventoryProvider interface {
+	GetItem(ctx context.Context, itemID int) (*dto.ItemDTO, error)
+	GetInventory(ctx context.Context, userID int64) ([]dto.InventoryItemDTO, error)
+	GrantItem(ctx context.Context, userID int64, itemID int, quantity int) (int, error)
+	EquipItem(ctx context.Context, userID int64, item dto.ItemDTO) error
+	UnequipItem(ctx context.Context, userID int64, itemID int) (bool, error)
+}
+
+type IPromoCodeProvider interface {
+	GetPromoCode(ctx context.Context, code string) (*dto.PromoCodeDTO, error)
+	CreatePromoRedemption(ctx context.Context, promo dto.PromoCodeDTO, userID int64) (int64, error)
+	DeletePromoRedemption(ctx context.Context, redemptionID int64) error
+	CreatePromoCodes(ctx context.Context, batch dto.PromoCodeBatchDTO, codes []string) ([]string, error)
+}
+
+type IPricingProvider interface {
+	GetPriceCampaigns(ctx context.Context, now time.Time, until time.Time) ([]dto.PriceCampaignDTO, error)
+	GetAllPriceCampaigns(ctx context.Context) ([]dto.PriceCampaignDTO, error)
+	CreatePriceCampaign(ctx context.Context, campaign dto.PriceCampaignDTO) (int, error)
+	SetPriceCampaignActive(ctx context.Context, campaignID int, isActive bool) error
+}
+
+type IFraudProvider interface {
+	FlagCharacter(ctx context.Context, flag dto.FraudFlagDTO) error
+	GetFlaggedCharacters(ctx context.Context, offset uint, limit uint) ([]dto.FraudFlagDTO, error)
+}
+
+// ITxProvider - runs fn in one transaction, commits it if fn returns nil.
+// Fn is run again if the transaction fails to serialize or deadlocks, so it must not have effects outside of repo.
+// Nested call runs in a savepoint of the outer transaction.
+type ITxProvider interface {
+	WithTx(ctx context.Context, fn func(repo TxRepository) error) error
+}
+
+// TxRepository - all providers bound to one transaction, reads see writes made in it
+type TxRepository interface {
+	IAppProvider
+	ICharacterProvider
+	ISeasonProvider
+	IReferralProvider
+	IDailyRewardProvider
+	IQuestProvider
+	IInventoryProvider
+	IPromoCodeProvider
+	IPricingProvider
+	IFraudProvider
+	ITxProvider
+}
@@ -0,0 +1,51 @@
+package seasonworker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SeasonRollover - service method called on every tick
+type SeasonRollover interface {
+	RolloverSeasons(ctx context.Context) error
+}
+
+// Worker - periodically finishes ended seasons and starts scheduled ones.
+// Rollover queries are idempotent, so every instance can run its own worker.
+type Worker struct {
+	log      *slog.Logger
+	service  SeasonRollover
+	interval time.Duration
+}
+
+func New(log *slog.Logger, service SeasonRollover, interval time.Duration) *Worker {
+	return &Worker{
+		log:      log,
+		service:  service,
+		interval: interval,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	const op = "workers.season.Run"
+	logger := w.log.With("op", op)
+
+	logger.Info("Starting season worker", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.service.RolloverSeasons(ctx); err != nil {
+			logger.Error("Season rollover failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Season worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_characters_total_mined_coins;
+
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS total_mined_coins,
+    DROP COLUMN IF EXISTS prestige;
@@ -0,0 +1,6 @@
+-- Поля для таблиц лидеров
+ALTER TABLE characters
+    ADD COLUMN prestige INTEGER NOT NULL DEFAULT 0 CHECK (prestige >= 0),
+    ADD COLUMN total_mined_coins BIGINT NOT NULL DEFAULT 0 CHECK (total_mined_coins >= 0);
+
+CREATE INDEX idx_characters_total_mined_coins ON characters(total_mined_coins DESC);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_seasons_status;
+DROP INDEX IF EXISTS idx_character_boosts_user_id_expires_at;
+
+DROP TABLE IF EXISTS season_tier_claims;
+DROP TABLE IF EXISTS character_season_progress;
+DROP TABLE IF EXISTS season_tier_rewards;
+DROP TABLE IF EXISTS season_tiers;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS character_boosts;
+DROP TABLE IF EXISTS character_owned_skins;
+
+ALTER TABLE character_skins
+    DROP COLUMN IF EXISTS is_exclusive;
@@ -0,0 +1,80 @@
+-- Эксклюзивные скины не открываются по уровню, только выдаются
+ALTER TABLE character_skins
+    ADD COLUMN is_exclusive BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Скины, полученные пользователем помимо открытия по уровню
+CREATE TABLE character_owned_skins (
+    user_id BIGINT NOT NULL,
+    skin_id INTEGER NOT NULL REFERENCES character_skins(skin_id),
+    source VARCHAR(32) NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, skin_id)
+);
+
+-- Временные бусты персонажа
+CREATE TABLE character_boosts (
+    boost_id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    boost_type VARCHAR(32) NOT NULL,
+    percent INTEGER NOT NULL CHECK (percent > 0),
+    source VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Сезоны
+CREATE TABLE seasons (
+    season_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    premium_price BIGINT NOT NULL DEFAULT 0 CHECK (premium_price >= 0),
+    status VARCHAR(16) NOT NULL DEFAULT 'scheduled', -- scheduled, active, finished
+    CHECK (ends_at > starts_at)
+);
+
+-- Уровни сезона
+CREATE TABLE season_tiers (
+    season_id INTEGER NOT NULL REFERENCES seasons(season_id) ON DELETE CASCADE,
+    tier_number INTEGER NOT NULL CHECK (tier_number > 0),
+    xp_required BIGINT NOT NULL CHECK (xp_required >= 0),
+    PRIMARY KEY (season_id, tier_number)
+);
+
+-- Награды уровней сезона, бесплатная и премиум ветки
+CREATE TABLE season_tier_rewards (
+    season_id INTEGER NOT NULL,
+    tier_number INTEGER NOT NULL,
+    is_premium BOOLEAN NOT NULL,
+    reward_type VARCHAR(16) NOT NULL, -- coins, boost, skin, level
+    amount BIGINT NOT NULL DEFAULT 0,
+    skin_id INTEGER REFERENCES character_skins(skin_id),
+    boost_type VARCHAR(32) NOT NULL DEFAULT '',
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (season_id, tier_number, is_premium),
+    FOREIGN KEY (season_id, tier_number) REFERENCES season_tiers(season_id, tier_number) ON DELETE CASCADE
+);
+
+-- Прогресс персонажа в сезоне
+CREATE TABLE character_season_progress (
+    season_id INTEGER NOT NULL REFERENCES seasons(season_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    xp BIGINT NOT NULL DEFAULT 0 CHECK (xp >= 0),
+    is_premium BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (season_id, user_id)
+);
+
+-- Полученные награды сезона
+CREATE TABLE season_tier_claims (
+    season_id INTEGER NOT NULL,
+    user_id BIGINT NOT NULL,
+    tier_number INTEGER NOT NULL,
+    is_premium BOOLEAN NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (season_id, user_id, tier_number, is_premium),
+    FOREIGN KEY (season_id, tier_number) REFERENCES season_tiers(season_id, tier_number) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_character_boosts_user_id_expires_at ON character_boosts(user_id, expires_at);
+CREATE INDEX idx_seasons_status ON seasons(status);
@@ -0,0 +1,7 @@
+ALTER TABLE character_skins
+    DROP CONSTRAINT IF EXISTS chk_character_skins_availability,
+    DROP CONSTRAINT IF EXISTS chk_character_skins_supply,
+    DROP COLUMN IF EXISTS sold_count,
+    DROP COLUMN IF EXISTS max_supply,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from;
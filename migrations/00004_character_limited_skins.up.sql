@@ -0,0 +1,8 @@
+-- Ограничения по времени и тиражу скинов
+ALTER TABLE character_skins
+    ADD COLUMN available_from TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN available_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN max_supply INTEGER CHECK (max_supply > 0),
+    ADD COLUMN sold_count INTEGER NOT NULL DEFAULT 0 CHECK (sold_count >= 0),
+    ADD CONSTRAINT chk_character_skins_supply CHECK (max_supply IS NULL OR sold_count <= max_supply),
+    ADD CONSTRAINT chk_character_skins_availability CHECK (available_until IS NULL OR available_from IS NULL OR available_until > available_from);
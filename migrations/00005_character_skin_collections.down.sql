@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_character_skins_collection_id;
+
+ALTER TABLE character_skins
+    DROP COLUMN IF EXISTS collection_id,
+    DROP COLUMN IF EXISTS rarity;
+
+DROP TABLE IF EXISTS skin_collections;
@@ -0,0 +1,14 @@
+-- Коллекции скинов, полная коллекция дает постоянный бонус
+CREATE TABLE skin_collections (
+    collection_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    bonus_type VARCHAR(32) NOT NULL, -- mining_force, game_multiplier
+    bonus_percent INTEGER NOT NULL CHECK (bonus_percent > 0)
+);
+
+ALTER TABLE character_skins
+    ADD COLUMN rarity VARCHAR(16) NOT NULL DEFAULT 'common'
+        CHECK (rarity IN ('common', 'uncommon', 'rare', 'epic', 'legendary')),
+    ADD COLUMN collection_id INTEGER REFERENCES skin_collections(collection_id);
+
+CREATE INDEX idx_character_skins_collection_id ON character_skins(collection_id);
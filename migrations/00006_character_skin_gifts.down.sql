@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_skin_gifts_to_user_id;
+DROP INDEX IF EXISTS idx_skin_gifts_from_user_id_created_at;
+
+DROP TABLE IF EXISTS skin_gifts;
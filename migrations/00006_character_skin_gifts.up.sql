@@ -0,0 +1,14 @@
+-- Подаренные скины
+CREATE TABLE skin_gifts (
+    gift_id SERIAL PRIMARY KEY,
+    from_user_id BIGINT NOT NULL,
+    to_user_id BIGINT NOT NULL,
+    skin_id INTEGER NOT NULL REFERENCES character_skins(skin_id),
+    price BIGINT NOT NULL CHECK (price >= 0),
+    payment_id VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX idx_skin_gifts_from_user_id_created_at ON skin_gifts(from_user_id, created_at);
+CREATE INDEX idx_skin_gifts_to_user_id ON skin_gifts(to_user_id);
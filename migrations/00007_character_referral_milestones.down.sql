@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS character_referral_milestones;
+DROP TABLE IF EXISTS character_referrals;
+
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS referrals_synced,
+    DROP COLUMN IF EXISTS referrals_count;
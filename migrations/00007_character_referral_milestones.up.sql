@@ -0,0 +1,20 @@
+-- Локальный счетчик рефералов, ведется по событиям referral_added
+ALTER TABLE characters
+    ADD COLUMN referrals_count INTEGER NOT NULL DEFAULT 0 CHECK (referrals_count >= 0),
+    ADD COLUMN referrals_synced BOOLEAN NOT NULL DEFAULT FALSE; -- счетчик сверен с referral service
+
+-- Учтенные рефералы, защищают счетчик от повторной доставки событий
+CREATE TABLE character_referrals (
+    user_id BIGINT NOT NULL,
+    referral_user_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, referral_user_id)
+);
+
+-- Выданные награды за количество рефералов
+CREATE TABLE character_referral_milestones (
+    user_id BIGINT NOT NULL,
+    referrals INTEGER NOT NULL CHECK (referrals > 0),
+    granted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, referrals)
+);
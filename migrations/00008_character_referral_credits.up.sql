@@ -0,0 +1,3 @@
+-- Рефералы, потраченные на покупку уровней
+ALTER TABLE characters
+    ADD COLUMN referrals_spent INTEGER NOT NULL DEFAULT 0 CHECK (referrals_spent >= 0);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS character_daily_claims;
+
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,13 @@
+-- Часовой пояс пользователя для расчета календарных дней
+ALTER TABLE characters
+    ADD COLUMN timezone VARCHAR(64);
+
+-- Ежедневные награды, один день пользователя - одна запись
+CREATE TABLE character_daily_claims (
+    user_id BIGINT NOT NULL,
+    claim_date DATE NOT NULL,
+    streak INTEGER NOT NULL CHECK (streak > 0),
+    timezone VARCHAR(64) NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, claim_date)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_quest_templates_action;
+DROP INDEX IF EXISTS idx_character_quests_user_id_period_start;
+
+DROP TABLE IF EXISTS character_quests;
+DROP TABLE IF EXISTS quest_templates;
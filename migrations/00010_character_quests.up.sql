@@ -0,0 +1,33 @@
+-- Шаблоны ежедневных и еженедельных заданий
+CREATE TABLE quest_templates (
+    template_id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    period VARCHAR(16) NOT NULL CHECK (period IN ('daily', 'weekly')),
+    action VARCHAR(32) NOT NULL, -- mining_claimed, game_finished, skin_changed, level_up, daily_claimed
+    target INTEGER NOT NULL CHECK (target > 0),
+    reward_type VARCHAR(16) NOT NULL, -- coins, boost, skin, level
+    amount BIGINT NOT NULL DEFAULT 0,
+    skin_id INTEGER REFERENCES character_skins(skin_id),
+    boost_type VARCHAR(32) NOT NULL DEFAULT '',
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Задания, выданные персонажу на день или неделю
+CREATE TABLE character_quests (
+    quest_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    template_id INTEGER NOT NULL REFERENCES quest_templates(template_id),
+    period_start DATE NOT NULL,
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress >= 0),
+    target INTEGER NOT NULL CHECK (target > 0),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, template_id, period_start)
+);
+
+CREATE INDEX idx_character_quests_user_id_period_start ON character_quests(user_id, period_start);
+CREATE INDEX idx_quest_templates_action ON quest_templates(action) WHERE is_active;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_characters_nickname_lower;
+
+ALTER TABLE characters DROP COLUMN IF EXISTS nickname_changed_at;
+ALTER TABLE characters DROP COLUMN IF EXISTS nickname;
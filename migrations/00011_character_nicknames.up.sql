@@ -0,0 +1,6 @@
+-- Пользовательский никнейм персонажа
+ALTER TABLE characters ADD COLUMN nickname VARCHAR(32);
+ALTER TABLE characters ADD COLUMN nickname_changed_at TIMESTAMP WITH TIME ZONE;
+
+-- Уникальность без учета регистра
+CREATE UNIQUE INDEX idx_characters_nickname_lower ON characters(LOWER(nickname));
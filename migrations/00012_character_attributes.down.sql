@@ -0,0 +1,3 @@
+ALTER TABLE characters DROP COLUMN IF EXISTS stamina;
+ALTER TABLE characters DROP COLUMN IF EXISTS luck;
+ALTER TABLE characters DROP COLUMN IF EXISTS strength;
@@ -0,0 +1,4 @@
+-- Распределенные очки характеристик персонажа
+ALTER TABLE characters ADD COLUMN strength INTEGER NOT NULL DEFAULT 0 CHECK (strength >= 0);
+ALTER TABLE characters ADD COLUMN luck INTEGER NOT NULL DEFAULT 0 CHECK (luck >= 0);
+ALTER TABLE characters ADD COLUMN stamina INTEGER NOT NULL DEFAULT 0 CHECK (stamina >= 0);
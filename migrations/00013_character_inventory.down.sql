@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS character_equipment_changes_trigger ON character_equipment;
+DROP TRIGGER IF EXISTS character_inventory_changes_trigger ON character_inventory;
+DROP FUNCTION IF EXISTS log_inventory_changes();
+
+DROP INDEX IF EXISTS idx_character_inventory_log_changed_at;
+DROP INDEX IF EXISTS idx_character_inventory_log_user_id;
+DROP INDEX IF EXISTS idx_character_inventory_user_id;
+
+DROP TABLE IF EXISTS character_inventory_log;
+DROP TABLE IF EXISTS character_equipment;
+DROP TABLE IF EXISTS character_inventory;
+DROP TABLE IF EXISTS items;
@@ -0,0 +1,75 @@
+-- Каталог предметов
+CREATE TABLE items (
+    item_id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    item_type VARCHAR(16) NOT NULL CHECK (item_type IN ('tool', 'pet', 'accessory')),
+    slot VARCHAR(16) NOT NULL, -- слот экипировки, в слоте может быть только один предмет
+    image_url VARCHAR(255) NOT NULL DEFAULT '',
+    mining_force_percent INTEGER NOT NULL DEFAULT 0,
+    mining_duration_percent INTEGER NOT NULL DEFAULT 0,
+    game_multiplier_percent INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- Инвентарь персонажа
+CREATE TABLE character_inventory (
+    inventory_id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    item_id INTEGER NOT NULL REFERENCES items(item_id),
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, item_id)
+);
+
+-- Экипированные предметы
+CREATE TABLE character_equipment (
+    user_id BIGINT NOT NULL,
+    slot VARCHAR(16) NOT NULL,
+    item_id INTEGER NOT NULL REFERENCES items(item_id),
+    equipped_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, slot)
+);
+
+-- Журнал изменений инвентаря и экипировки
+CREATE TABLE character_inventory_log (
+    log_id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    table_name VARCHAR(64) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    operation VARCHAR(10) NOT NULL,
+    old_data JSONB,
+    new_data JSONB
+);
+
+-- Создание функции для триггера
+CREATE OR REPLACE FUNCTION log_inventory_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO character_inventory_log (user_id, table_name, operation, new_data)
+        VALUES (NEW.user_id, TG_TABLE_NAME, TG_OP, row_to_json(NEW));
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO character_inventory_log (user_id, table_name, operation, old_data, new_data)
+        VALUES (NEW.user_id, TG_TABLE_NAME, TG_OP, row_to_json(OLD), row_to_json(NEW));
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO character_inventory_log (user_id, table_name, operation, old_data)
+        VALUES (OLD.user_id, TG_TABLE_NAME, TG_OP, row_to_json(OLD));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Создание триггеров
+CREATE TRIGGER character_inventory_changes_trigger
+AFTER INSERT OR UPDATE OR DELETE ON character_inventory
+FOR EACH ROW EXECUTE FUNCTION log_inventory_changes();
+
+CREATE TRIGGER character_equipment_changes_trigger
+AFTER INSERT OR UPDATE OR DELETE ON character_equipment
+FOR EACH ROW EXECUTE FUNCTION log_inventory_changes();
+
+CREATE INDEX idx_character_inventory_user_id ON character_inventory(user_id);
+CREATE INDEX idx_character_inventory_log_user_id ON character_inventory_log(user_id);
+CREATE INDEX idx_character_inventory_log_changed_at ON character_inventory_log(changed_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_promo_redemptions_promo_code_id_user_id;
+DROP INDEX IF EXISTS idx_promo_code_rewards_promo_code_id;
+DROP INDEX IF EXISTS idx_promo_codes_batch_id;
+
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_code_rewards;
+DROP TABLE IF EXISTS promo_codes;
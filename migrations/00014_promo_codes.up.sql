@@ -0,0 +1,35 @@
+-- Промокоды
+CREATE TABLE promo_codes (
+    promo_code_id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE, -- хранится в верхнем регистре
+    batch_id VARCHAR(64) NOT NULL,
+    max_redemptions INTEGER CHECK (max_redemptions > 0), -- NULL - без глобального лимита
+    redemptions_count INTEGER NOT NULL DEFAULT 0,
+    per_user_limit INTEGER NOT NULL DEFAULT 1 CHECK (per_user_limit > 0),
+    min_level INTEGER NOT NULL DEFAULT 1,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Набор наград промокода
+CREATE TABLE promo_code_rewards (
+    promo_code_id INTEGER NOT NULL REFERENCES promo_codes(promo_code_id) ON DELETE CASCADE,
+    reward_type VARCHAR(16) NOT NULL, -- coins, boost, skin, level
+    amount BIGINT NOT NULL DEFAULT 0,
+    skin_id INTEGER REFERENCES character_skins(skin_id),
+    boost_type VARCHAR(32) NOT NULL DEFAULT '',
+    duration_minutes INTEGER NOT NULL DEFAULT 0
+);
+
+-- Активации промокодов
+CREATE TABLE promo_redemptions (
+    redemption_id BIGSERIAL PRIMARY KEY,
+    promo_code_id INTEGER NOT NULL REFERENCES promo_codes(promo_code_id),
+    user_id BIGINT NOT NULL,
+    redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_promo_codes_batch_id ON promo_codes(batch_id);
+CREATE INDEX idx_promo_code_rewards_promo_code_id ON promo_code_rewards(promo_code_id);
+CREATE INDEX idx_promo_redemptions_promo_code_id_user_id ON promo_redemptions(promo_code_id, user_id);
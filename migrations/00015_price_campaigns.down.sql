@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_price_campaigns_ends_at;
+
+DROP TABLE IF EXISTS price_campaigns;
@@ -0,0 +1,22 @@
+-- Скидочные кампании на повышение уровня и скины
+CREATE TABLE price_campaigns (
+    campaign_id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    target VARCHAR(16) NOT NULL CHECK (target IN ('level_up', 'skin')),
+    target_id INTEGER, -- номер уровня или skin_id, NULL - все уровни или скины
+    discount_type VARCHAR(16) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value BIGINT NOT NULL CHECK (discount_value > 0),
+    -- Сегмент игроков, NULL - без ограничения
+    min_level INTEGER,
+    max_level INTEGER,
+    min_prestige INTEGER,
+    max_prestige INTEGER,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100)
+);
+
+CREATE INDEX idx_price_campaigns_ends_at ON price_campaigns(ends_at) WHERE is_active;
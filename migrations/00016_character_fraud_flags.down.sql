@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_character_fraud_flags_updated_at;
+
+DROP TABLE IF EXISTS character_fraud_flags;
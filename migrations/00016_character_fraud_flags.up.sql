@@ -0,0 +1,12 @@
+-- Персонажи, отмеченные антифродом для ручной проверки
+CREATE TABLE character_fraud_flags (
+    user_id BIGINT PRIMARY KEY,
+    score INTEGER NOT NULL,
+    reasons TEXT NOT NULL, -- сработавшие сигналы через запятую
+    operation VARCHAR(32) NOT NULL,
+    flags_count INTEGER NOT NULL DEFAULT 1,
+    flagged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_character_fraud_flags_updated_at ON character_fraud_flags(updated_at);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS character_owned_skins_version_trigger ON character_owned_skins;
+DROP FUNCTION IF EXISTS bump_character_version_on_owned_skins();
+
+DROP TRIGGER IF EXISTS character_version_trigger ON characters;
+DROP FUNCTION IF EXISTS bump_character_version();
+
+ALTER TABLE characters DROP COLUMN IF EXISTS version;
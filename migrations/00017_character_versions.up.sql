@@ -0,0 +1,31 @@
+-- Версия персонажа растет с каждым изменением, кэш не перезаписывает новую версию агрегата старой
+ALTER TABLE characters ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION bump_character_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER character_version_trigger
+BEFORE UPDATE ON characters
+FOR EACH ROW EXECUTE FUNCTION bump_character_version();
+
+-- Скины игрока входят в агрегат, их изменение тоже меняет версию персонажа
+CREATE OR REPLACE FUNCTION bump_character_version_on_owned_skins()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE characters SET version = version + 1 WHERE user_id = OLD.user_id;
+    ELSE
+        UPDATE characters SET version = version + 1 WHERE user_id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER character_owned_skins_version_trigger
+AFTER INSERT OR DELETE ON character_owned_skins
+FOR EACH ROW EXECUTE FUNCTION bump_character_version_on_owned_skins();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_character_mining_claims_claimed_at;
+
+DROP TABLE IF EXISTS character_mining_claims;
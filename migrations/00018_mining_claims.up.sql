@@ -0,0 +1,9 @@
+-- Обработанные события добычи, повторная доставка события не начисляет монеты второй раз
+CREATE TABLE character_mining_claims (
+    claim_id VARCHAR(64) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES characters(user_id) ON DELETE CASCADE,
+    coins BIGINT NOT NULL CHECK (coins > 0),
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_character_mining_claims_claimed_at ON character_mining_claims(claimed_at);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS character_boosts_version_trigger ON character_boosts;
+DROP TRIGGER IF EXISTS character_equipment_version_trigger ON character_equipment;
+
+DROP FUNCTION IF EXISTS bump_character_version_on_user_rows();
@@ -0,0 +1,20 @@
+-- Экипировка и бусты входят в агрегат персонажа, их изменение тоже меняет версию персонажа
+CREATE OR REPLACE FUNCTION bump_character_version_on_user_rows()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE characters SET version = version + 1 WHERE user_id = OLD.user_id;
+    ELSE
+        UPDATE characters SET version = version + 1 WHERE user_id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER character_equipment_version_trigger
+AFTER INSERT OR UPDATE OR DELETE ON character_equipment
+FOR EACH ROW EXECUTE FUNCTION bump_character_version_on_user_rows();
+
+CREATE TRIGGER character_boosts_version_trigger
+AFTER INSERT OR UPDATE OR DELETE ON character_boosts
+FOR EACH ROW EXECUTE FUNCTION bump_character_version_on_user_rows();
@@ -0,0 +1,3 @@
+ALTER TABLE seasons DROP CONSTRAINT IF EXISTS seasons_premium_price_check;
+ALTER TABLE seasons DROP COLUMN IF EXISTS premium_free;
+ALTER TABLE seasons ALTER COLUMN premium_price SET DEFAULT 0;
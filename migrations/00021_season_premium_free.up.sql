@@ -0,0 +1,7 @@
+-- Цена премиума задается явно, бесплатный премиум включается отдельным флагом
+ALTER TABLE seasons ALTER COLUMN premium_price DROP DEFAULT;
+ALTER TABLE seasons ADD COLUMN premium_free BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Уже созданные сезоны с нулевой ценой не проверяем, их нужно явно пометить бесплатными или задать цену
+ALTER TABLE seasons ADD CONSTRAINT seasons_premium_price_check
+    CHECK (premium_free OR premium_price > 0) NOT VALID;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS character_version_trigger ON characters;
+
+CREATE TRIGGER character_version_trigger
+BEFORE UPDATE ON characters
+FOR EACH ROW EXECUTE FUNCTION bump_character_version();
@@ -0,0 +1,17 @@
+-- Версия меняется только при изменении колонок, которые входят в агрегат персонажа.
+-- Майнинг, рефералы и часовой пояс не сбрасывают кэш агрегата.
+-- Триггеры дочерних таблиц увеличивают version явно, для них условие не нужно
+DROP TRIGGER IF EXISTS character_version_trigger ON characters;
+
+CREATE TRIGGER character_version_trigger
+BEFORE UPDATE ON characters
+FOR EACH ROW
+WHEN (
+    OLD.current_level IS DISTINCT FROM NEW.current_level
+    OR OLD.current_skin_id IS DISTINCT FROM NEW.current_skin_id
+    OR OLD.nickname IS DISTINCT FROM NEW.nickname
+    OR OLD.strength IS DISTINCT FROM NEW.strength
+    OR OLD.luck IS DISTINCT FROM NEW.luck
+    OR OLD.stamina IS DISTINCT FROM NEW.stamina
+)
+EXECUTE FUNCTION bump_character_version();